@@ -0,0 +1,112 @@
+package supermuxer
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"net/http"
+)
+
+// ABConfig configures the middleware created by NewABTestingMiddleware.
+type ABConfig struct {
+	// Percentage is the percentage of the traffic, between 0 and 100, handled by VariantHandler.
+	Percentage float64
+	// VariantHandler handles the requests assigned to the variant. It is required.
+	VariantHandler http.HandlerFunc
+	// BucketKeyFunc returns the key assigning every request, e.g. the user ID, so the same key is always assigned
+	// to the same group, whatever the cookie says. Defaults to a random assignment, kept by the cookie.
+	BucketKeyFunc func(*http.Request) string
+	// CookieName is the cookie keeping, or only recording with BucketKeyFunc, the group of the client.
+	// Defaults to 'ab_group'.
+	CookieName string
+}
+
+const (
+	// ABGroupControl is the group of the requests handled by the next handler.
+	ABGroupControl = "control"
+	// ABGroupVariant is the group of the requests handled by the VariantHandler of ABConfig.
+	ABGroupVariant = "variant"
+)
+
+type abGroupContextKey struct{}
+
+// NewABTestingMiddleware creates a middleware that splits the traffic between the next handler and VariantHandler,
+// assigning Percentage of the requests to the variant by the hash of their bucket key. The assigned group,
+// ABGroupControl or ABGroupVariant, is stored in the request context, readable with ABGroupFromContext, and in
+// a cookie. Without BucketKeyFunc, the cookie keeps the random group of the client, even when the percentage changes.
+// With BucketKeyFunc, the group always comes from the hash and the cookie only records it, as clients can change it.
+// It panics if VariantHandler is nil.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	experiment := supermuxer.NewABTestingMiddleware(supermuxer.ABConfig{
+//		Percentage:     10,
+//		VariantHandler: newCheckoutHandler,
+//		BucketKeyFunc:  func(r *http.Request) string { return r.Header.Get("X-User-ID") },
+//	})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(experiment).Get("/checkout", checkoutHandler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /checkout'
+//		with newCheckoutHandler for 10% of the users
+func NewABTestingMiddleware(cfg ABConfig) MiddlewareFunc {
+	if cfg.VariantHandler == nil {
+		panic("supermuxer: A/B testing variant handler is required")
+	}
+
+	if cfg.CookieName == "" {
+		cfg.CookieName = "ab_group"
+	}
+
+	assign := func(r *http.Request) string {
+		bucket := rand.Float64() * 100
+		if cfg.BucketKeyFunc != nil {
+			hash := fnv.New64a()
+			hash.Write([]byte(cfg.BucketKeyFunc(r)))
+			bucket = float64(hash.Sum64()%10000) / 100
+		}
+
+		if bucket < cfg.Percentage {
+			return ABGroupVariant
+		}
+
+		return ABGroupControl
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var recorded string
+			if cookie, err := r.Cookie(cfg.CookieName); err == nil && (cookie.Value == ABGroupControl || cookie.Value == ABGroupVariant) {
+				recorded = cookie.Value
+			}
+
+			// The cookie is only trusted for random assignments, which cannot be computed again.
+			group := recorded
+			if group == "" || cfg.BucketKeyFunc != nil {
+				group = assign(r)
+			}
+
+			if group != recorded {
+				http.SetCookie(w, &http.Cookie{Name: cfg.CookieName, Value: group, Path: "/", HttpOnly: true})
+			}
+
+			r = r.WithContext(context.WithValue(r.Context(), abGroupContextKey{}, group))
+			if group == ABGroupVariant {
+				cfg.VariantHandler(w, r)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// ABGroupFromContext returns the group assigned by the middleware created by NewABTestingMiddleware,
+// ABGroupControl or ABGroupVariant, or an empty string if there is none.
+func ABGroupFromContext(ctx context.Context) string {
+	group, _ := ctx.Value(abGroupContextKey{}).(string)
+	return group
+}
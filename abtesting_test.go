@@ -0,0 +1,123 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestABTestingMiddleware(t *testing.T) {
+	newHandler := func(cfg ABConfig) http.HandlerFunc {
+		cfg.VariantHandler = func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("variant " + ABGroupFromContext(r.Context())))
+		}
+
+		return NewABTestingMiddleware(cfg)(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("control " + ABGroupFromContext(r.Context())))
+		})
+	}
+
+	byUser := func(r *http.Request) string { return r.Header.Get("X-User-ID") }
+
+	send := func(handler http.HandlerFunc, userID string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+		req.Header.Set("X-User-ID", userID)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("same bucket key gets the same handler", func(t *testing.T) {
+		handler := newHandler(ABConfig{Percentage: 50, BucketKeyFunc: byUser})
+
+		for i := range 20 {
+			userID := "user-" + strconv.Itoa(i)
+			first := send(handler, userID, nil).Body.String()
+
+			for range 5 {
+				if got := send(handler, userID, nil).Body.String(); got != first {
+					t.Fatalf("%s got %q, then %q", userID, first, got)
+				}
+			}
+		}
+	})
+
+	t.Run("split ratio", func(t *testing.T) {
+		handler := newHandler(ABConfig{Percentage: 30, BucketKeyFunc: byUser})
+
+		variants := 0
+		const users = 10000
+		for i := range users {
+			if send(handler, "user-"+strconv.Itoa(i), nil).Body.String() == "variant variant" {
+				variants++
+			}
+		}
+
+		if ratio := float64(variants) / users; ratio < 0.27 || ratio > 0.33 {
+			t.Fatalf("variant ratio = %.3f, want about 0.30", ratio)
+		}
+	})
+
+	t.Run("cookie does not override the bucket key", func(t *testing.T) {
+		handler := newHandler(ABConfig{Percentage: 50, BucketKeyFunc: byUser})
+
+		for i := range 20 {
+			userID := "user-" + strconv.Itoa(i)
+			want := send(handler, userID, nil).Body.String()
+
+			for _, group := range []string{ABGroupControl, ABGroupVariant} {
+				rec := send(handler, userID, &http.Cookie{Name: "ab_group", Value: group})
+				if rec.Body.String() != want {
+					t.Fatalf("%s with the %s cookie got %q, want %q", userID, group, rec.Body.String(), want)
+				}
+			}
+		}
+	})
+
+	t.Run("random assignment kept by the cookie", func(t *testing.T) {
+		handler := newHandler(ABConfig{Percentage: 50, CookieName: "experiment"})
+
+		rec := send(handler, "", nil)
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != "experiment" || !cookies[0].HttpOnly {
+			t.Fatalf("cookies = %v, want the HttpOnly 'experiment' cookie", cookies)
+		}
+
+		group := cookies[0].Value
+		for range 10 {
+			next := send(handler, "", cookies[0])
+			if next.Body.String() != group+" "+group {
+				t.Fatalf("body = %q, want the %s group", next.Body.String(), group)
+			}
+
+			if next.Header().Get("Set-Cookie") != "" {
+				t.Fatalf("Set-Cookie = %q, want none for the recorded group", next.Header().Get("Set-Cookie"))
+			}
+		}
+	})
+
+	t.Run("percentage bounds", func(t *testing.T) {
+		for _, tt := range []struct {
+			percentage float64
+			want       string
+		}{{percentage: 0, want: "control control"}, {percentage: 100, want: "variant variant"}} {
+			handler := newHandler(ABConfig{Percentage: tt.percentage})
+			for range 20 {
+				if got := send(handler, "", nil).Body.String(); got != tt.want {
+					t.Fatalf("percentage %v got %q, want %q", tt.percentage, got, tt.want)
+				}
+			}
+		}
+	})
+
+	t.Run("missing variant handler", func(t *testing.T) {
+		assertPanics(t, "A/B testing variant handler is required", func() {
+			NewABTestingMiddleware(ABConfig{Percentage: 10})
+		})
+	})
+}
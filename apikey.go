@@ -0,0 +1,85 @@
+package supermuxer
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// APIKeyConfig configures the middleware created by NewAPIKeyMiddleware.
+type APIKeyConfig struct {
+	// Keys lists the valid API keys.
+	Keys []string
+	// HeaderName is the request header holding the key. Defaults to 'X-API-Key'.
+	HeaderName string
+	// QueryParam is the query parameter holding the key when the header is missing. Empty disables it.
+	QueryParam string
+	// OnUnauthorized handles the requests with a missing or invalid key. Defaults to 401.
+	OnUnauthorized http.HandlerFunc
+}
+
+type apiKeyContextKey struct{}
+
+// NewAPIKeyMiddleware creates a middleware that requires one of the API keys in the header or, when the header is
+// missing, in the query parameter. The keys are compared in constant time. The matched key is stored in the request
+// context, readable with APIKeyFromContext.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	apiKey := supermuxer.NewAPIKeyMiddleware(supermuxer.APIKeyConfig{Keys: keys, QueryParam: "api_key"})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(apiKey).Get("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /reports'
+//		responding with 401 unless the 'X-API-Key' header or the 'api_key' query parameter holds a valid key
+func NewAPIKeyMiddleware(cfg APIKeyConfig) MiddlewareFunc {
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = "X-API-Key"
+	}
+
+	if cfg.OnUnauthorized == nil {
+		cfg.OnUnauthorized = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		}
+	}
+
+	hashes := make([][sha256.Size]byte, len(cfg.Keys))
+	for i, key := range cfg.Keys {
+		hashes[i] = sha256.Sum256([]byte(key))
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(cfg.HeaderName)
+			if key == "" && cfg.QueryParam != "" {
+				key = r.URL.Query().Get(cfg.QueryParam)
+			}
+
+			given := sha256.Sum256([]byte(key))
+			matched := 0
+
+			// Every key is compared, so the response time does not tell which one is closer.
+			for _, hash := range hashes {
+				matched |= subtle.ConstantTimeCompare(given[:], hash[:])
+			}
+
+			if key == "" || matched != 1 {
+				cfg.OnUnauthorized(w, r)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+		}
+	}
+}
+
+// APIKeyFromContext returns the API key matched by the middleware created by NewAPIKeyMiddleware,
+// or an empty string if there is none.
+func APIKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(apiKeyContextKey{}).(string)
+	return key
+}
@@ -0,0 +1,74 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	handler := NewAPIKeyMiddleware(APIKeyConfig{Keys: []string{"key-1", "key-2"}, QueryParam: "api_key"})(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(APIKeyFromContext(r.Context())))
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		status int
+		key    string
+	}{
+		{name: "header", target: "/", header: "key-2", status: http.StatusOK, key: "key-2"},
+		{name: "query param", target: "/?api_key=key-1", status: http.StatusOK, key: "key-1"},
+		{name: "header before query param", target: "/?api_key=key-1", header: "key-2", status: http.StatusOK, key: "key-2"},
+		{name: "missing key", target: "/", status: http.StatusUnauthorized},
+		{name: "invalid key", target: "/", header: "key-3", status: http.StatusUnauthorized},
+		{name: "invalid header with a valid query param", target: "/?api_key=key-1", header: "key-3", status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("X-API-Key", tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if tt.status == http.StatusOK && rec.Body.String() != tt.key {
+				t.Fatalf("context key = %q, want %q", rec.Body.String(), tt.key)
+			}
+		})
+	}
+
+	t.Run("custom header and handler", func(t *testing.T) {
+		handler := NewAPIKeyMiddleware(APIKeyConfig{
+			Keys:       []string{"key-1"},
+			HeaderName: "Authorization",
+			OnUnauthorized: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+		})(textHandler("ok"))
+
+		req := httptest.NewRequest(http.MethodGet, "/?api_key=key-1", nil)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("status without a query param = %d, want %d", rec.Code, http.StatusForbidden)
+		}
+
+		req.Header.Set("Authorization", "key-1")
+		rec = httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+}
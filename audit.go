@@ -0,0 +1,114 @@
+package supermuxer
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEvent is the record of a request written by the middleware created by NewAuditLogMiddleware.
+type AuditEvent struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	RemoteAddr string    `json:"remote_addr"`
+	// UserID is the user set with SetAuditUserID during the request.
+	UserID     string  `json:"user_id,omitempty"`
+	StatusCode int     `json:"status_code"`
+	DurationMs float64 `json:"duration_ms"`
+	// RequestID is the request ID stored by the middleware created by NewRequestIDMiddleware.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// AuditSink writes the events of the middleware created by NewAuditLogMiddleware.
+// Implementations must be safe for concurrent use.
+type AuditSink interface {
+	WriteAuditEvent(evt AuditEvent) error
+}
+
+type auditUserIDContextKey struct{}
+
+// fileAuditSink is an AuditSink that appends the events to a file as JSON lines.
+type fileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFileAuditSink creates an AuditSink that appends the events to the file at path as JSON lines,
+// creating the file if needed. The returned sink implements io.Closer to close the file.
+func NewFileAuditSink(path string) (AuditSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileAuditSink{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+func (s *fileAuditSink) WriteAuditEvent(evt AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.enc.Encode(evt)
+}
+
+func (s *fileAuditSink) Close() error {
+	return s.file.Close()
+}
+
+// NewAuditLogMiddleware creates a middleware that writes an AuditEvent to the sink for every request, after the next
+// handler returns. The handlers identify the user of the event with SetAuditUserID. Errors of the sink are logged
+// with the default slog logger.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	sink, err := supermuxer.NewFileAuditSink("audit.log")
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewAuditLogMiddleware(sink)).Delete("/accounts/{id}", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'DELETE /accounts/{id}'
+//		appending an audit event of every request to 'audit.log'
+func NewAuditLogMiddleware(sink AuditSink) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			userID := new(string)
+			sw := newStatusWriter(w)
+
+			defer func() {
+				evt := AuditEvent{
+					Timestamp:  start,
+					Method:     r.Method,
+					Path:       r.URL.Path,
+					RemoteAddr: r.RemoteAddr,
+					UserID:     *userID,
+					StatusCode: sw.Status(),
+					DurationMs: durationMs(time.Since(start)),
+					RequestID:  RequestIDFromContext(r.Context()),
+				}
+
+				if err := sink.WriteAuditEvent(evt); err != nil {
+					slog.Error("supermuxer: writing audit event", "error", err)
+				}
+			}()
+
+			next(sw, r.WithContext(context.WithValue(r.Context(), auditUserIDContextKey{}, userID)))
+		}
+	}
+}
+
+// SetAuditUserID sets the user of the AuditEvent of the request written by the middleware created by
+// NewAuditLogMiddleware. It does nothing if the middleware does not handle the request.
+func SetAuditUserID(r *http.Request, userID string) {
+	if holder, ok := r.Context().Value(auditUserIDContextKey{}).(*string); ok {
+		*holder = userID
+	}
+}
@@ -0,0 +1,121 @@
+package supermuxer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type failingAuditSink struct{ calls int }
+
+func (s *failingAuditSink) WriteAuditEvent(AuditEvent) error {
+	s.calls++
+	return errors.New("sink unavailable")
+}
+
+func TestAuditLogMiddleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileAuditSink(path)
+	if err != nil {
+		t.Fatalf("NewFileAuditSink() error = %v", err)
+	}
+
+	handler := Chain(
+		NewRequestIDMiddleware(RequestIDOptions{}),
+		NewAuditLogMiddleware(sink),
+	)(func(w http.ResponseWriter, r *http.Request) {
+		SetAuditUserID(r, "user-42")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	before := time.Now()
+	req := httptest.NewRequest(http.MethodDelete, "/accounts/7", nil)
+	req.RemoteAddr = "203.0.113.9:4312"
+	req.Header.Set("X-Request-ID", "req-1")
+	handler(httptest.NewRecorder(), req)
+
+	second := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+	second.Header.Set("X-Request-ID", "req-2")
+	handler(httptest.NewRecorder(), second)
+
+	if err := sink.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("os.Open() error = %v", err)
+	}
+	defer file.Close()
+
+	events := []AuditEvent{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var evt AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &evt); err != nil {
+			t.Fatalf("json.Unmarshal(%q) error = %v", scanner.Text(), err)
+		}
+		events = append(events, evt)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("events = %d, want 2", len(events))
+	}
+
+	evt := events[0]
+	if evt.Method != http.MethodDelete || evt.Path != "/accounts/7" || evt.RemoteAddr != "203.0.113.9:4312" {
+		t.Fatalf("request fields = %s %s %s, want DELETE /accounts/7 203.0.113.9:4312", evt.Method, evt.Path, evt.RemoteAddr)
+	}
+
+	if evt.UserID != "user-42" {
+		t.Fatalf("UserID = %q, want %q", evt.UserID, "user-42")
+	}
+
+	if evt.StatusCode != http.StatusNoContent {
+		t.Fatalf("StatusCode = %d, want %d", evt.StatusCode, http.StatusNoContent)
+	}
+
+	if evt.RequestID != "req-1" {
+		t.Fatalf("RequestID = %q, want %q", evt.RequestID, "req-1")
+	}
+
+	if evt.Timestamp.Before(before.Truncate(time.Second)) || evt.Timestamp.After(time.Now()) {
+		t.Fatalf("Timestamp = %v, want between %v and now", evt.Timestamp, before)
+	}
+
+	if evt.DurationMs < 0 {
+		t.Fatalf("DurationMs = %v, want non-negative", evt.DurationMs)
+	}
+
+	if events[1].RequestID != "req-2" || events[1].Method != http.MethodGet {
+		t.Fatalf("second event = %s %q, want GET \"req-2\"", events[1].Method, events[1].RequestID)
+	}
+}
+
+func TestAuditLogMiddlewareSinkError(t *testing.T) {
+	sink := &failingAuditSink{}
+	handler := NewAuditLogMiddleware(sink)(textHandler("ok"))
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("response = %d %q, want 200 \"ok\"", rec.Code, rec.Body.String())
+	}
+
+	if sink.calls != 1 {
+		t.Fatalf("sink calls = %d, want 1", sink.calls)
+	}
+}
+
+func TestSetAuditUserIDWithoutMiddleware(t *testing.T) {
+	// It must not panic when the middleware does not handle the request.
+	SetAuditUserID(httptest.NewRequest(http.MethodGet, "/", nil), "user-42")
+}
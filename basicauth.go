@@ -0,0 +1,52 @@
+package supermuxer
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// NewBasicAuthMiddleware creates a middleware that validates the 'Authorization: Basic' credentials of every request
+// with the validator. Requests with missing or invalid credentials get 401 with the
+// 'WWW-Authenticate: Basic realm="Restricted"' header. BasicAuthUsers creates a validator for fixed credentials.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	basicAuth := supermuxer.NewBasicAuthMiddleware(supermuxer.BasicAuthUsers(map[string]string{"admin": password}))
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(basicAuth).Get("/admin", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /admin' only for the admin user
+func NewBasicAuthMiddleware(validator func(user, pass string) bool) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			user, pass, ok := r.BasicAuth()
+			if !ok || !validator(user, pass) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// BasicAuthUsers creates a validator for NewBasicAuthMiddleware that accepts the users with their passwords.
+// The credentials are compared in constant time, so the response time does not leak them.
+func BasicAuthUsers(users map[string]string) func(user, pass string) bool {
+	hashes := make(map[string][sha256.Size]byte, len(users))
+	for user, pass := range users {
+		hashes[user] = sha256.Sum256([]byte(pass))
+	}
+
+	return func(user, pass string) bool {
+		expected, exists := hashes[user]
+		given := sha256.Sum256([]byte(pass))
+
+		return subtle.ConstantTimeCompare(given[:], expected[:]) == 1 && exists
+	}
+}
@@ -0,0 +1,48 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBasicAuthMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(NewBasicAuthMiddleware(BasicAuthUsers(map[string]string{"admin": "s3cret"}))).Get("/admin", textHandler("admin"))
+
+	tests := []struct {
+		name   string
+		user   string
+		pass   string
+		status int
+	}{
+		{name: "missing credentials", status: http.StatusUnauthorized},
+		{name: "wrong password", user: "admin", pass: "wrong", status: http.StatusUnauthorized},
+		{name: "unknown user", user: "guest", pass: "s3cret", status: http.StatusUnauthorized},
+		{name: "correct credentials", user: "admin", pass: "s3cret", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.user != "" {
+				req.SetBasicAuth(tt.user, tt.pass)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != `Basic realm="Restricted"` {
+				t.Fatalf("WWW-Authenticate = %q, want the Basic challenge", rec.Header().Get("WWW-Authenticate"))
+			}
+
+			if tt.status == http.StatusOK && rec.Body.String() != "admin" {
+				t.Fatalf("body = %q, want %q", rec.Body.String(), "admin")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package supermuxer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+type requestBodyContextKey struct{}
+
+// cachedBody reads a buffered request body, rewinding to its start once the end is reached,
+// so every part of the chain can read the whole body.
+type cachedBody struct {
+	*bytes.Reader
+}
+
+func (b cachedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	if err == io.EOF {
+		b.Reader.Seek(0, io.SeekStart)
+	}
+
+	return n, err
+}
+
+func (b cachedBody) Close() error {
+	b.Reader.Seek(0, io.SeekStart)
+	return nil
+}
+
+// NewRequestBodyCacheMiddleware creates a middleware that buffers the whole request body in memory, so the next
+// middlewares and handlers can all read it: once a read reaches the end, the body starts over.
+// The buffer is stored in the request context, readable with RequestBodyFromContext.
+// Requests whose body cannot be read get 400.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewRequestBodyCacheMiddleware(), bodyLogger).Post("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /users'
+//		where both bodyLogger and handler read the whole body
+func NewRequestBodyCacheMiddleware() MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			cached := r.WithContext(context.WithValue(r.Context(), requestBodyContextKey{}, body))
+			cached.Body = cachedBody{bytes.NewReader(body)}
+
+			next(w, cached)
+
+			// The previous middlewares can read the body too once the next handlers are done.
+			r.Body = cachedBody{bytes.NewReader(body)}
+		}
+	}
+}
+
+// RequestBodyFromContext returns the request body buffered by the middleware created by
+// NewRequestBodyCacheMiddleware. The returned bytes must not be modified.
+func RequestBodyFromContext(ctx context.Context) ([]byte, bool) {
+	body, ok := ctx.Value(requestBodyContextKey{}).([]byte)
+	return body, ok
+}
@@ -0,0 +1,94 @@
+package supermuxer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRequestBodyCacheMiddleware(t *testing.T) {
+	payload := `{"name":"supermuxer"}`
+
+	t.Run("every reader gets the whole body", func(t *testing.T) {
+		var reads []string
+		read := func(r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			reads = append(reads, string(body))
+		}
+
+		reader := func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				read(r)
+				next(w, r)
+			}
+		}
+
+		var contextBody []byte
+		handler := Chain(NewRequestBodyCacheMiddleware(), reader)(func(w http.ResponseWriter, r *http.Request) {
+			read(r)
+			read(r)
+			contextBody, _ = RequestBodyFromContext(r.Context())
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(payload))
+		handler(httptest.NewRecorder(), req)
+
+		// The middlewares before the cache read the body once the next handlers are done.
+		read(req)
+
+		if len(reads) != 4 {
+			t.Fatalf("reads = %d, want 4", len(reads))
+		}
+
+		for i, body := range reads {
+			if body != payload {
+				t.Fatalf("read %d = %q, want %q", i+1, body, payload)
+			}
+		}
+
+		if string(contextBody) != payload {
+			t.Fatalf("RequestBodyFromContext() = %q, want %q", contextBody, payload)
+		}
+	})
+
+	t.Run("close rewinds a partial read", func(t *testing.T) {
+		var body string
+		handler := NewRequestBodyCacheMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+			r.Body.Read(make([]byte, 4))
+			r.Body.Close()
+
+			rest, _ := io.ReadAll(r.Body)
+			body = string(rest)
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(payload)))
+
+		if body != payload {
+			t.Fatalf("body after Close = %q, want %q", body, payload)
+		}
+	})
+
+	t.Run("unreadable body", func(t *testing.T) {
+		called := false
+		handler := NewRequestBodyCacheMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/users", iotest.ErrReader(errors.New("connection reset"))))
+
+		if rec.Code != http.StatusBadRequest || called {
+			t.Fatalf("status = %d, handler called = %v, want %d without calling the handler", rec.Code, called, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("without middleware", func(t *testing.T) {
+		if _, ok := RequestBodyFromContext(httptest.NewRequest(http.MethodPost, "/", nil).Context()); ok {
+			t.Fatal("RequestBodyFromContext() ok = true, want false")
+		}
+	})
+}
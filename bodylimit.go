@@ -0,0 +1,112 @@
+package supermuxer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// limitedBody records whether reading the request body went over the http.MaxBytesReader limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+
+	return n, err
+}
+
+// bodyLimitWriter replaces the response with 413 when the request body went over the limit
+// before the response was committed.
+type bodyLimitWriter struct {
+	http.ResponseWriter
+	body        *limitedBody
+	wroteHeader bool
+	rejected    bool
+}
+
+func (w *bodyLimitWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if w.body.exceeded {
+		w.rejected = true
+		http.Error(w.ResponseWriter, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bodyLimitWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.rejected {
+		return len(b), nil
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *bodyLimitWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewBodySizeLimitMiddleware creates a middleware that limits the request body to maxBytes with http.MaxBytesReader.
+// Requests declaring a larger 'Content-Length' are rejected with 413 right away. When a handler reads over the limit,
+// the response is replaced with 413 if it was not committed yet, including when the handler panics with the
+// *http.MaxBytesError.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewBodySizeLimitMiddleware(1 << 20))
+//	superRouter.Post("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /users'
+//		responding with 413 for bodies larger than 1 MB
+func NewBodySizeLimitMiddleware(maxBytes int64) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, maxBytes)}
+			lw := &bodyLimitWriter{ResponseWriter: w, body: body}
+			r.Body = body
+
+			defer func() {
+				if v := recover(); v != nil {
+					var maxBytesErr *http.MaxBytesError
+					if err, ok := v.(error); !ok || !errors.As(err, &maxBytesErr) {
+						panic(v)
+					}
+					body.exceeded = true
+				}
+
+				if body.exceeded && !lw.wroteHeader {
+					lw.WriteHeader(http.StatusRequestEntityTooLarge)
+				}
+			}()
+
+			next(lw, r)
+		}
+	}
+}
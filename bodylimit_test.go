@@ -0,0 +1,71 @@
+package supermuxer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodySizeLimitMiddleware(t *testing.T) {
+	readBody := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "cannot read the body", http.StatusBadRequest)
+			return
+		}
+
+		w.Write(body)
+	}
+
+	post := func(handler http.HandlerFunc, body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		return rec
+	}
+
+	handler := NewBodySizeLimitMiddleware(8)(readBody)
+
+	t.Run("below the limit", func(t *testing.T) {
+		if rec := post(handler, "12345678", true); rec.Code != http.StatusOK || rec.Body.String() != "12345678" {
+			t.Fatalf("response = %d %q, want 200 with the body", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("declared length above the limit", func(t *testing.T) {
+		called := false
+		handler := NewBodySizeLimitMiddleware(8)(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+
+		if rec := post(handler, "123456789", false); rec.Code != http.StatusRequestEntityTooLarge || called {
+			t.Fatalf("status = %d, handler called = %t, want 413 without calling the handler", rec.Code, called)
+		}
+	})
+
+	t.Run("read above the limit", func(t *testing.T) {
+		rec := post(handler, "123456789", true)
+		if rec.Code != http.StatusRequestEntityTooLarge || strings.Contains(rec.Body.String(), "cannot read the body") {
+			t.Fatalf("response = %d %q, want 413 instead of the handler response", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("panic above the limit", func(t *testing.T) {
+		handler := NewBodySizeLimitMiddleware(8)(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := io.ReadAll(r.Body); err != nil {
+				panic(err)
+			}
+		})
+
+		if rec := post(handler, "123456789", true); rec.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package supermuxer
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// NewRequestBodyTransformMiddleware creates a middleware that rewrites the request body with the transform before
+// the next handler, updating its 'Content-Length'. Requests whose body cannot be read or transformed get 500.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewRequestBodyTransformMiddleware(addTenantField)).Post("/orders", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /orders'
+//		with the body rewritten by addTenantField
+func NewRequestBodyTransformMiddleware(transform func([]byte) ([]byte, error)) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err == nil {
+				body, err = transform(body)
+			}
+
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+			next(w, r)
+		}
+	}
+}
+
+// NewResponseBodyTransformMiddleware creates a middleware that buffers the response body of the next handler and
+// rewrites it with the transform before sending it, updating its 'Content-Length'. Responses that cannot be
+// transformed are replaced with 500.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewResponseBodyTransformMiddleware(removeSecrets)).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		with the response body rewritten by removeSecrets
+func NewResponseBodyTransformMiddleware(transform func([]byte) ([]byte, error)) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			bw := newBufferWriter(w)
+			next(bw, r)
+
+			body, err := transform(bw.body.Bytes())
+			if err != nil {
+				w.Header().Del("Content-Length")
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			bw.body.Reset()
+			bw.body.Write(body)
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+
+			bw.flush()
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package supermuxer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRequestBodyTransformMiddleware(t *testing.T) {
+	upper := func(body []byte) ([]byte, error) { return bytes.ToUpper(body), nil }
+	failing := func(body []byte) ([]byte, error) { return nil, errors.New("invalid body") }
+
+	t.Run("transformed", func(t *testing.T) {
+		var body string
+		var contentLength int64
+		handler := NewRequestBodyTransformMiddleware(func(body []byte) ([]byte, error) {
+			return append(bytes.ToUpper(body), '!'), nil
+		})(func(w http.ResponseWriter, r *http.Request) {
+			read, _ := io.ReadAll(r.Body)
+			body, contentLength = string(read), r.ContentLength
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("supermuxer")))
+
+		if body != "SUPERMUXER!" {
+			t.Fatalf("body = %q, want %q", body, "SUPERMUXER!")
+		}
+
+		if contentLength != int64(len(body)) {
+			t.Fatalf("ContentLength = %d, want %d", contentLength, len(body))
+		}
+	})
+
+	t.Run("through a server", func(t *testing.T) {
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(NewRequestBodyTransformMiddleware(upper)).Post("/users", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.Write([]byte(r.Header.Get("Content-Length") + " " + string(body)))
+		})
+
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		resp, err := server.Client().Post(server.URL+"/users", "text/plain", strings.NewReader("supermuxer"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if body, _ := io.ReadAll(resp.Body); string(body) != "10 SUPERMUXER" {
+			t.Fatalf("body = %q, want %q", body, "10 SUPERMUXER")
+		}
+	})
+
+	t.Run("transform error", func(t *testing.T) {
+		called := false
+		handler := NewRequestBodyTransformMiddleware(failing)(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("supermuxer")))
+
+		if rec.Code != http.StatusInternalServerError || called {
+			t.Fatalf("status = %d, handler called = %v, want %d without calling the handler", rec.Code, called, http.StatusInternalServerError)
+		}
+	})
+}
+
+func TestResponseBodyTransformMiddleware(t *testing.T) {
+	t.Run("transformed", func(t *testing.T) {
+		handler := NewResponseBodyTransformMiddleware(func(body []byte) ([]byte, error) {
+			return []byte(`{"data":` + string(body) + `}`), nil
+		})(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", "2")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("[]"))
+		})
+
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		want := `{"data":[]}`
+		if rec.Code != http.StatusCreated || rec.Body.String() != want {
+			t.Fatalf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusCreated, want)
+		}
+
+		if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(want)) {
+			t.Fatalf("Content-Length = %q, want %q", got, strconv.Itoa(len(want)))
+		}
+	})
+
+	t.Run("transform error", func(t *testing.T) {
+		handler := NewResponseBodyTransformMiddleware(func(body []byte) ([]byte, error) {
+			return nil, errors.New("invalid body")
+		})(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "7")
+			w.Write([]byte("private"))
+		})
+
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+		if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "private") {
+			t.Fatalf("response = %d %q, want %d without the original body", rec.Code, rec.Body.String(), http.StatusInternalServerError)
+		}
+	})
+}
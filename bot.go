@@ -0,0 +1,71 @@
+package supermuxer
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+// BotConfig configures the middleware created by NewBotDetectionMiddleware.
+type BotConfig struct {
+	// DenyBotUserAgents lists the regular expressions of the denied 'User-Agent' headers.
+	DenyBotUserAgents []string
+	// OnBotDetected handles the requests of the denied bots. Defaults to 403.
+	OnBotDetected http.HandlerFunc
+	// AllowGoodBots lets the well-known search engine and link preview crawlers, such as Googlebot and Bingbot,
+	// bypass the deny rules.
+	AllowGoodBots bool
+}
+
+// goodBotUserAgent matches the 'User-Agent' headers of the well-known crawlers allowed by BotConfig.AllowGoodBots.
+var goodBotUserAgent = regexp.MustCompile(`(?i)googlebot|bingbot|duckduckbot|slurp|baiduspider|yandexbot|applebot|facebookexternalhit|linkedinbot|twitterbot`)
+
+// NewBotDetectionMiddleware creates a middleware that handles the requests whose 'User-Agent' header matches one of
+// the deny patterns with OnBotDetected. The patterns are compiled once, when the middleware is created.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//   - An error if a pattern is not a valid regular expression.
+//
+// Example:
+//
+//	bots, err := supermuxer.NewBotDetectionMiddleware(supermuxer.BotConfig{DenyBotUserAgents: []string{`(?i)bot|crawler|spider`}, AllowGoodBots: true})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(bots).Get("/products", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /products'
+//		responding with 403 to the crawlers other than the well-known ones
+func NewBotDetectionMiddleware(cfg BotConfig) (MiddlewareFunc, error) {
+	patterns := make([]*regexp.Regexp, len(cfg.DenyBotUserAgents))
+	for i, expr := range cfg.DenyBotUserAgents {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("supermuxer: invalid bot user agent pattern %q: %w", expr, err)
+		}
+
+		patterns[i] = pattern
+	}
+
+	if cfg.OnBotDetected == nil {
+		cfg.OnBotDetected = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			userAgent := r.UserAgent()
+
+			if !cfg.AllowGoodBots || !goodBotUserAgent.MatchString(userAgent) {
+				for _, pattern := range patterns {
+					if pattern.MatchString(userAgent) {
+						cfg.OnBotDetected(w, r)
+						return
+					}
+				}
+			}
+
+			next(w, r)
+		}
+	}, nil
+}
@@ -0,0 +1,69 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBotDetectionMiddleware(t *testing.T) {
+	deny := []string{`(?i)bot|crawler|spider`, `^curl/`}
+
+	tests := []struct {
+		name          string
+		allowGoodBots bool
+		userAgent     string
+		status        int
+	}{
+		{name: "browser", userAgent: "Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0", status: http.StatusOK},
+		{name: "empty user agent", status: http.StatusOK},
+		{name: "matching pattern", userAgent: "SomeCrawler/1.0", status: http.StatusForbidden},
+		{name: "second pattern", userAgent: "curl/8.5.0", status: http.StatusForbidden},
+		{name: "good bot denied", userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1)", status: http.StatusForbidden},
+		{name: "good bot allowed", allowGoodBots: true, userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1)", status: http.StatusOK},
+		{name: "other bot with good bots allowed", allowGoodBots: true, userAgent: "ScraperBot/0.1", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			middleware, err := NewBotDetectionMiddleware(BotConfig{DenyBotUserAgents: deny, AllowGoodBots: tt.allowGoodBots})
+			if err != nil {
+				t.Fatalf("NewBotDetectionMiddleware() error = %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("User-Agent", tt.userAgent)
+
+			rec := httptest.NewRecorder()
+			middleware(textHandler("ok"))(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+
+	t.Run("custom handler", func(t *testing.T) {
+		middleware, _ := NewBotDetectionMiddleware(BotConfig{
+			DenyBotUserAgents: deny,
+			OnBotDetected:     func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTooManyRequests) },
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("User-Agent", "curl/8.5.0")
+
+		rec := httptest.NewRecorder()
+		middleware(textHandler("ok"))(rec, req)
+
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		middleware, err := NewBotDetectionMiddleware(BotConfig{DenyBotUserAgents: []string{`bot(`}})
+		if err == nil || middleware != nil {
+			t.Fatalf("NewBotDetectionMiddleware() = %v, %v, want an error", middleware, err)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package supermuxer
+
+import (
+	"net/http"
+)
+
+// BulkheadConfig configures the middleware created by NewBulkheadMiddleware.
+type BulkheadConfig struct {
+	// MaxConcurrent is the maximum of requests running the next handler at once. It must be positive.
+	MaxConcurrent int
+	// QueueDepth is the maximum of requests waiting for one of the running requests to finish.
+	QueueDepth int
+}
+
+// NewBulkheadMiddleware creates a middleware that isolates the next handler, so a slow route cannot take every
+// connection of the server. At most MaxConcurrent requests run at once, and at most QueueDepth requests wait for
+// them, until their context is cancelled. The requests beyond the queue get 503 with the 'Retry-After: 1' header
+// right away. Every call creates a separate bulkhead, so it is meant to be added to a single route or group.
+// It panics if MaxConcurrent is not positive or QueueDepth is negative.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	bulkhead := supermuxer.NewBulkheadMiddleware(supermuxer.BulkheadConfig{MaxConcurrent: 4, QueueDepth: 16})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.SubGroup("").AddMiddlewares(bulkhead).Post("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /reports'
+//		with at most 4 requests at once and 16 waiting, responding with 503 to the others
+func NewBulkheadMiddleware(cfg BulkheadConfig) MiddlewareFunc {
+	if cfg.MaxConcurrent <= 0 || cfg.QueueDepth < 0 {
+		panic("supermuxer: bulkhead maximum of concurrent requests must be positive and queue depth not negative")
+	}
+
+	// admitted holds the running and the waiting requests, running holds the running requests.
+	admitted := make(chan struct{}, cfg.MaxConcurrent+cfg.QueueDepth)
+	running := make(chan struct{}, cfg.MaxConcurrent)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case admitted <- struct{}{}:
+			default:
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-admitted }()
+
+			select {
+			case running <- struct{}{}:
+			case <-r.Context().Done():
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-running }()
+
+			next(w, r)
+		}
+	}
+}
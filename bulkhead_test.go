@@ -0,0 +1,88 @@
+package supermuxer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestBulkheadMiddleware(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+
+	handler := NewBulkheadMiddleware(BulkheadConfig{MaxConcurrent: 1, QueueDepth: 1})(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	results := make([]*httptest.ResponseRecorder, 2)
+	send := func(i int) {
+		defer wg.Done()
+
+		// The probes below may take the queue for a moment, so the request is sent again until it is queued.
+		for {
+			results[i] = httptest.NewRecorder()
+			handler(results[i], httptest.NewRequest(http.MethodPost, "/reports", nil))
+
+			if results[i].Code != http.StatusServiceUnavailable {
+				return
+			}
+		}
+	}
+
+	wg.Add(1)
+	go send(0)
+	<-started
+
+	wg.Add(1)
+	go send(1)
+
+	// A cancelled request is admitted while the queue has room and gives up waiting, without Retry-After.
+	// Once the second request holds the queue, the cancelled request is rejected with Retry-After instead.
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var overflow *httptest.ResponseRecorder
+	for overflow == nil {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/reports", nil).WithContext(cancelled))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status of the cancelled request = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+
+		if rec.Header().Get("Retry-After") != "" {
+			overflow = rec
+		}
+	}
+
+	if got := overflow.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("Retry-After = %q, want %q", got, "1")
+	}
+
+	select {
+	case <-started:
+		t.Fatal("queued request ran while the bulkhead was full")
+	default:
+	}
+
+	close(release)
+	wg.Wait()
+
+	for i, rec := range results {
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status of request %d = %d, want %d", i, rec.Code, http.StatusOK)
+		}
+	}
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		want := "bulkhead maximum of concurrent requests must be positive and queue depth not negative"
+
+		assertPanics(t, want, func() { NewBulkheadMiddleware(BulkheadConfig{}) })
+		assertPanics(t, want, func() { NewBulkheadMiddleware(BulkheadConfig{MaxConcurrent: 1, QueueDepth: -1}) })
+	})
+}
@@ -0,0 +1,216 @@
+package supermuxer
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CachedResponse is a response stored by the middleware created by NewCacheMiddleware.
+type CachedResponse struct {
+	Status   int
+	Header   http.Header
+	Body     []byte
+	StoredAt time.Time
+}
+
+// CacheStore stores the responses of the middleware created by NewCacheMiddleware.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the response stored for the key, and whether there is one that did not expire.
+	Get(key string) (CachedResponse, bool)
+	// Set stores the response for the key for the ttl.
+	Set(key string, resp CachedResponse, ttl time.Duration)
+	// Delete removes the response stored for the key.
+	Delete(key string)
+}
+
+// CacheConfig configures the middleware created by NewCacheMiddleware.
+type CacheConfig struct {
+	// TTL is how long the responses are cached. Defaults to 1 minute.
+	TTL time.Duration
+	// KeyFunc identifies the cached responses. Defaults to the method and URL of the request.
+	KeyFunc func(*http.Request) string
+	// Store stores the responses. Defaults to a new InMemoryCacheStore.
+	Store CacheStore
+}
+
+// cacheSweepInterval is the minimum time between two sweeps of the expired responses of an InMemoryCacheStore.
+const cacheSweepInterval = time.Minute
+
+type inMemoryCacheEntry struct {
+	resp      CachedResponse
+	expiresAt time.Time
+}
+
+// InMemoryCacheStore is a CacheStore that keeps the responses in memory.
+// The expired responses are evicted when they are read, and swept at most once a minute when new ones are stored.
+type InMemoryCacheStore struct {
+	mu        sync.Mutex
+	entries   map[string]inMemoryCacheEntry
+	lastSweep time.Time
+}
+
+// NewInMemoryCacheStore creates an empty InMemoryCacheStore.
+func NewInMemoryCacheStore() *InMemoryCacheStore {
+	return &InMemoryCacheStore{entries: map[string]inMemoryCacheEntry{}, lastSweep: time.Now()}
+}
+
+func (s *InMemoryCacheStore) Get(key string) (CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return CachedResponse{}, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return CachedResponse{}, false
+	}
+
+	return entry.resp, true
+}
+
+func (s *InMemoryCacheStore) Set(key string, resp CachedResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if s.entries == nil {
+		s.entries = map[string]inMemoryCacheEntry{}
+	}
+
+	if now.Sub(s.lastSweep) >= cacheSweepInterval {
+		for entryKey, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, entryKey)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	s.entries[key] = inMemoryCacheEntry{resp: resp, expiresAt: now.Add(ttl)}
+}
+
+func (s *InMemoryCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// NewCacheMiddleware creates a middleware that caches the 2xx responses of 'GET' requests for the TTL.
+// Cached responses are written without calling the next handler, with the 'Age' and 'X-Cache: HIT' headers,
+// while the others get 'X-Cache: MISS'. Requests with an 'Authorization' header are neither served from nor stored in
+// the cache, and neither are responses setting cookies, with 'Cache-Control: private' or 'no-store', or with a 'Vary'
+// header, as they depend on more than the cache key. Only the headers set by the next handlers are judged and stored,
+// so the headers set by the middlewares added before, like the 'Vary' header of NewGzipMiddleware, do not prevent
+// caching, and they are set again by these middlewares for the cached responses.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	cache := supermuxer.NewCacheMiddleware(supermuxer.CacheConfig{TTL: 5 * time.Minute})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(cache).Get("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /reports'
+//		calling the handler at most once every 5 minutes
+func NewCacheMiddleware(cfg CacheConfig) MiddlewareFunc {
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Minute
+	}
+
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(r *http.Request) string {
+			return r.Method + " " + r.URL.String()
+		}
+	}
+
+	if cfg.Store == nil {
+		cfg.Store = NewInMemoryCacheStore()
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet || r.Header.Get("Authorization") != "" {
+				next(w, r)
+				return
+			}
+
+			key := cfg.KeyFunc(r)
+			header := w.Header()
+
+			if resp, ok := cfg.Store.Get(key); ok {
+				for name, values := range resp.Header {
+					if _, exists := header[name]; !exists {
+						header[name] = slices.Clone(values)
+					}
+				}
+
+				header.Set("Age", strconv.Itoa(int(time.Since(resp.StoredAt).Seconds())))
+				header.Set("X-Cache", "HIT")
+				w.WriteHeader(resp.Status)
+				w.Write(resp.Body)
+				return
+			}
+
+			header.Set("X-Cache", "MISS")
+			before := header.Clone()
+			bw := newBufferWriter(w)
+			next(bw, r)
+
+			stored := headerChanges(before, header)
+			status := bw.Status()
+			if status >= 200 && status < 300 && cacheable(stored) {
+				if bw.body.Len() > 0 && header.Get("Content-Type") == "" {
+					header.Set("Content-Type", http.DetectContentType(bw.body.Bytes()))
+					stored.Set("Content-Type", header.Get("Content-Type"))
+				}
+
+				cfg.Store.Set(key, CachedResponse{
+					Status:   status,
+					Header:   stored,
+					Body:     slices.Clone(bw.body.Bytes()),
+					StoredAt: time.Now(),
+				}, cfg.TTL)
+			}
+
+			bw.flush()
+		}
+	}
+}
+
+// headerChanges returns the header fields added or changed since before, with only the values appended to the
+// fields that kept their previous values, such as the 'Vary' names added after the ones of the outer middlewares.
+func headerChanges(before, after http.Header) http.Header {
+	changes := make(http.Header)
+
+	for name, values := range after {
+		previous := before[name]
+		if len(previous) <= len(values) && slices.Equal(previous, values[:len(previous)]) {
+			values = values[len(previous):]
+		}
+
+		if len(values) > 0 {
+			changes[name] = slices.Clone(values)
+		}
+	}
+
+	return changes
+}
+
+// cacheable reports whether a response with the header can be stored by the middleware created by NewCacheMiddleware.
+func cacheable(header http.Header) bool {
+	if header.Get("Set-Cookie") != "" || len(header.Values("Vary")) > 0 {
+		return false
+	}
+
+	return !headerHasToken(header, "Cache-Control", "private") && !headerHasToken(header, "Cache-Control", "no-store")
+}
@@ -0,0 +1,240 @@
+package supermuxer
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestCacheMiddleware(t *testing.T) {
+	newHandler := func(cfg CacheConfig, calls *int, header http.Header) http.HandlerFunc {
+		return NewCacheMiddleware(cfg)(func(w http.ResponseWriter, r *http.Request) {
+			*calls++
+			for name, values := range header {
+				w.Header()[name] = values
+			}
+			w.Write([]byte("report " + strconv.Itoa(*calls)))
+		})
+	}
+
+	send := func(handler http.HandlerFunc, method, target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("miss then hit", func(t *testing.T) {
+		calls := 0
+		handler := newHandler(CacheConfig{}, &calls, nil)
+
+		miss := send(handler, http.MethodGet, "/reports", nil)
+		hit := send(handler, http.MethodGet, "/reports", nil)
+
+		if calls != 1 {
+			t.Fatalf("handler calls = %d, want 1", calls)
+		}
+
+		if got := miss.Header().Get("X-Cache"); got != "MISS" {
+			t.Fatalf("first X-Cache = %q, want %q", got, "MISS")
+		}
+
+		if got := hit.Header().Get("X-Cache"); got != "HIT" {
+			t.Fatalf("second X-Cache = %q, want %q", got, "HIT")
+		}
+
+		if hit.Body.String() != "report 1" || hit.Code != http.StatusOK {
+			t.Fatalf("cached response = %d %q, want 200 \"report 1\"", hit.Code, hit.Body.String())
+		}
+
+		if hit.Header().Get("Age") != "0" || hit.Header().Get("Content-Type") == "" {
+			t.Fatalf("cached headers = %v, want Age 0 and a Content-Type", hit.Header())
+		}
+	})
+
+	t.Run("different keys miss", func(t *testing.T) {
+		calls := 0
+		handler := newHandler(CacheConfig{}, &calls, nil)
+
+		send(handler, http.MethodGet, "/reports?page=1", nil)
+		send(handler, http.MethodGet, "/reports?page=2", nil)
+
+		if calls != 2 {
+			t.Fatalf("handler calls = %d, want 2", calls)
+		}
+	})
+
+	t.Run("custom key", func(t *testing.T) {
+		calls := 0
+		handler := newHandler(CacheConfig{KeyFunc: func(r *http.Request) string { return r.URL.Path }}, &calls, nil)
+
+		send(handler, http.MethodGet, "/reports?page=1", nil)
+		send(handler, http.MethodGet, "/reports?page=2", nil)
+
+		if calls != 1 {
+			t.Fatalf("handler calls = %d, want 1", calls)
+		}
+	})
+
+	t.Run("non-GET bypass", func(t *testing.T) {
+		calls := 0
+		handler := newHandler(CacheConfig{}, &calls, nil)
+
+		for range 2 {
+			rec := send(handler, http.MethodPost, "/reports", nil)
+			if rec.Header().Get("X-Cache") != "" {
+				t.Fatalf("X-Cache = %q, want none", rec.Header().Get("X-Cache"))
+			}
+		}
+
+		if calls != 2 {
+			t.Fatalf("handler calls = %d, want 2", calls)
+		}
+	})
+
+	t.Run("TTL expiry", func(t *testing.T) {
+		calls := 0
+		handler := newHandler(CacheConfig{TTL: 10 * time.Millisecond}, &calls, nil)
+
+		send(handler, http.MethodGet, "/reports", nil)
+		time.Sleep(20 * time.Millisecond)
+		rec := send(handler, http.MethodGet, "/reports", nil)
+
+		if calls != 2 || rec.Header().Get("X-Cache") != "MISS" {
+			t.Fatalf("handler calls = %d, X-Cache = %q, want 2 and %q", calls, rec.Header().Get("X-Cache"), "MISS")
+		}
+	})
+
+	t.Run("failed responses are not cached", func(t *testing.T) {
+		calls := 0
+		handler := NewCacheMiddleware(CacheConfig{})(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			http.NotFound(w, r)
+		})
+
+		send(handler, http.MethodGet, "/reports", nil)
+		send(handler, http.MethodGet, "/reports", nil)
+
+		if calls != 2 {
+			t.Fatalf("handler calls = %d, want 2", calls)
+		}
+	})
+
+	t.Run("headers of the outer middlewares", func(t *testing.T) {
+		calls := 0
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(NewGzipMiddlewareWithMinSize(gzip.DefaultCompression, 5), NewCacheMiddleware(CacheConfig{})).
+			Get("/reports", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Write([]byte("report " + strconv.Itoa(calls)))
+			}).
+			Get("/localized", func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Add("Vary", "Accept-Language")
+				w.Write([]byte("report " + strconv.Itoa(calls)))
+			})
+
+		for i, want := range []string{"MISS", "HIT", "HIT"} {
+			rec := send(mux.ServeHTTP, http.MethodGet, "/reports", http.Header{"Accept-Encoding": {"gzip"}})
+
+			if got := rec.Header().Get("X-Cache"); got != want {
+				t.Fatalf("request %d X-Cache = %q, want %q", i+1, got, want)
+			}
+
+			if got := rec.Header().Values("Vary"); len(got) != 1 || got[0] != "Accept-Encoding" {
+				t.Fatalf("request %d Vary = %q, want %q", i+1, got, "Accept-Encoding")
+			}
+
+			reader, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatalf("request %d gzip.NewReader() error = %v", i+1, err)
+			}
+
+			if body, _ := io.ReadAll(reader); string(body) != "report 1" {
+				t.Fatalf("request %d body = %q, want %q", i+1, body, "report 1")
+			}
+		}
+
+		send(mux.ServeHTTP, http.MethodGet, "/localized", nil)
+		send(mux.ServeHTTP, http.MethodGet, "/localized", nil)
+		if calls != 3 {
+			t.Fatalf("handler calls = %d, want 3, as the handler adds a 'Vary' header", calls)
+		}
+	})
+
+	skips := []struct {
+		name           string
+		requestHeader  http.Header
+		responseHeader http.Header
+	}{
+		{name: "authorization request", requestHeader: http.Header{"Authorization": {"Bearer token"}}},
+		{name: "private response", responseHeader: http.Header{"Cache-Control": {"private, max-age=60"}}},
+		{name: "no-store response", responseHeader: http.Header{"Cache-Control": {"no-store"}}},
+		{name: "vary response", responseHeader: http.Header{"Vary": {"Accept-Language"}}},
+		{name: "cookie response", responseHeader: http.Header{"Set-Cookie": {"session=abc"}}},
+	}
+
+	for _, tt := range skips {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := newHandler(CacheConfig{}, &calls, tt.responseHeader)
+
+			send(handler, http.MethodGet, "/reports", tt.requestHeader)
+			rec := send(handler, http.MethodGet, "/reports", tt.requestHeader)
+
+			if calls != 2 {
+				t.Fatalf("handler calls = %d, want 2", calls)
+			}
+
+			if rec.Body.String() != "report 2" {
+				t.Fatalf("body = %q, want %q", rec.Body.String(), "report 2")
+			}
+		})
+	}
+}
+
+func TestInMemoryCacheStore(t *testing.T) {
+	t.Run("zero value", func(t *testing.T) {
+		var store InMemoryCacheStore
+		store.Set("key", CachedResponse{Status: http.StatusOK}, time.Minute)
+
+		if _, ok := store.Get("key"); !ok {
+			t.Fatal("Get did not return the stored response")
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := NewInMemoryCacheStore()
+		store.Set("key", CachedResponse{Status: http.StatusOK}, time.Minute)
+		store.Delete("key")
+
+		if _, ok := store.Get("key"); ok {
+			t.Fatal("Get returned a deleted response")
+		}
+	})
+
+	t.Run("set sweeps expired responses", func(t *testing.T) {
+		store := NewInMemoryCacheStore()
+		store.Set("old", CachedResponse{Status: http.StatusOK}, time.Millisecond)
+		time.Sleep(2 * time.Millisecond)
+
+		store.lastSweep = time.Now().Add(-cacheSweepInterval)
+		store.Set("new", CachedResponse{Status: http.StatusOK}, time.Minute)
+
+		if _, ok := store.entries["old"]; ok {
+			t.Fatal("expired response was not swept")
+		}
+
+		if _, ok := store.entries["new"]; !ok {
+			t.Fatal("new response was not stored")
+		}
+	})
+}
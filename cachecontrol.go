@@ -0,0 +1,103 @@
+package supermuxer
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cacheControlWriter sets the 'Cache-Control' header right before the headers are written, unless the handler set it.
+type cacheControlWriter struct {
+	http.ResponseWriter
+	directives  string
+	noCache     bool
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) setHeader() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	if header.Get("Cache-Control") != "" {
+		return
+	}
+
+	header.Set("Cache-Control", w.directives)
+
+	if w.noCache {
+		header.Set("Pragma", "no-cache")
+	}
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	w.setHeader()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *cacheControlWriter) Flush() {
+	w.setHeader()
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *cacheControlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewCacheControlMiddleware creates a middleware that sets the 'Cache-Control' header of every response to the
+// directives, unless the handler set it, so a handler can override it. The 'no-store' and 'no-cache' directives
+// also set the 'Pragma: no-cache' header for the HTTP/1.0 caches.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewCacheControlMiddleware("private, max-age=60")).Get("/profile", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /profile'
+//		with the 'Cache-Control: private, max-age=60' header
+func NewCacheControlMiddleware(directives string) MiddlewareFunc {
+	noCache := false
+	for _, directive := range strings.Split(directives, ",") {
+		name, _, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if strings.EqualFold(name, "no-store") || strings.EqualFold(name, "no-cache") {
+			noCache = true
+		}
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			cw := &cacheControlWriter{ResponseWriter: w, directives: directives, noCache: noCache}
+			defer cw.setHeader()
+
+			next(cw, r)
+		}
+	}
+}
+
+// NewNoCacheMiddleware creates a middleware, like NewCacheControlMiddleware, that forbids caching the responses with
+// the 'Cache-Control: no-store, no-cache, must-revalidate' and 'Pragma: no-cache' headers.
+func NewNoCacheMiddleware() MiddlewareFunc {
+	return NewCacheControlMiddleware("no-store, no-cache, must-revalidate")
+}
+
+// NewPublicCacheMiddleware creates a middleware, like NewCacheControlMiddleware, that lets the browsers and the
+// shared caches keep the responses for maxAge, rounded down to whole seconds, with the
+// 'Cache-Control: public, max-age=<seconds>' header.
+func NewPublicCacheMiddleware(maxAge time.Duration) MiddlewareFunc {
+	return NewCacheControlMiddleware("public, max-age=" + strconv.Itoa(max(0, int(maxAge.Seconds()))))
+}
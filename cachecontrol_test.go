@@ -0,0 +1,84 @@
+package supermuxer
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCacheControlMiddleware(t *testing.T) {
+	tests := []struct {
+		name             string
+		middleware       MiddlewareFunc
+		handler          http.HandlerFunc
+		wantCacheControl string
+		wantPragma       string
+	}{
+		{
+			name:             "directives",
+			middleware:       NewCacheControlMiddleware("private, max-age=60"),
+			handler:          textHandler("profile"),
+			wantCacheControl: "private, max-age=60",
+		},
+		{
+			name:             "no-cache directive",
+			middleware:       NewCacheControlMiddleware("private, No-Cache"),
+			handler:          textHandler("profile"),
+			wantCacheControl: "private, No-Cache",
+			wantPragma:       "no-cache",
+		},
+		{
+			name:             "empty response",
+			middleware:       NewCacheControlMiddleware("private, max-age=60"),
+			handler:          func(http.ResponseWriter, *http.Request) {},
+			wantCacheControl: "private, max-age=60",
+		},
+		{
+			name:       "status without body",
+			middleware: NewNoCacheMiddleware(),
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			wantCacheControl: "no-store, no-cache, must-revalidate",
+			wantPragma:       "no-cache",
+		},
+		{
+			name:             "public cache",
+			middleware:       NewPublicCacheMiddleware(90*time.Second + 500*time.Millisecond),
+			handler:          textHandler("catalog"),
+			wantCacheControl: "public, max-age=90",
+		},
+		{
+			name:             "negative public cache",
+			middleware:       NewPublicCacheMiddleware(-time.Minute),
+			handler:          textHandler("catalog"),
+			wantCacheControl: "public, max-age=0",
+		},
+		{
+			name:       "header set by the handler",
+			middleware: NewNoCacheMiddleware(),
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "public, max-age=300")
+				w.Write([]byte("catalog"))
+			},
+			wantCacheControl: "public, max-age=300",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			New(mux).AddMiddlewares(tt.middleware).Get("/profile", tt.handler)
+
+			header := serve(mux, http.MethodGet, "/profile").Result().Header
+
+			if got := header.Get("Cache-Control"); got != tt.wantCacheControl {
+				t.Fatalf("Cache-Control = %q, want %q", got, tt.wantCacheControl)
+			}
+
+			if got := header.Get("Pragma"); got != tt.wantPragma {
+				t.Fatalf("Pragma = %q, want %q", got, tt.wantPragma)
+			}
+		})
+	}
+}
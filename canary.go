@@ -0,0 +1,55 @@
+package supermuxer
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+// CanaryConfig configures the middleware created by NewCanaryMiddleware.
+type CanaryConfig struct {
+	// TriggerHeader is the header sending the requests to CanaryHandler. It is required.
+	TriggerHeader string
+	// TriggerValue is the value of TriggerHeader sending the requests to CanaryHandler. Empty matches any value.
+	TriggerValue string
+	// CanaryHandler handles the requests with the trigger header. It is required.
+	CanaryHandler http.HandlerFunc
+}
+
+// NewCanaryMiddleware creates a middleware that sends the requests with the 'TriggerHeader: TriggerValue' header
+// to CanaryHandler instead of the next handler, e.g. the requests of the internal users. The handler taken by every
+// request is logged with the default slog logger at the debug level. It panics if TriggerHeader or CanaryHandler
+// are missing.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	canary := supermuxer.NewCanaryMiddleware(supermuxer.CanaryConfig{TriggerHeader: "X-Canary", TriggerValue: "always", CanaryHandler: newSearchHandler})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(canary).Get("/search", searchHandler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /search'
+//		with newSearchHandler for the requests with the 'X-Canary: always' header
+func NewCanaryMiddleware(cfg CanaryConfig) MiddlewareFunc {
+	if cfg.TriggerHeader == "" || cfg.CanaryHandler == nil {
+		panic("supermuxer: canary trigger header and handler are required")
+	}
+
+	triggerHeader := http.CanonicalHeaderKey(cfg.TriggerHeader)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			values, ok := r.Header[triggerHeader]
+			if ok && (cfg.TriggerValue == "" || slices.Contains(values, cfg.TriggerValue)) {
+				slog.Debug("supermuxer: routing request to canary", "method", r.Method, "path", r.URL.Path)
+				cfg.CanaryHandler(w, r)
+				return
+			}
+
+			slog.Debug("supermuxer: routing request to stable", "method", r.Method, "path", r.URL.Path)
+			next(w, r)
+		}
+	}
+}
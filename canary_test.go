@@ -0,0 +1,56 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCanaryMiddleware(t *testing.T) {
+	tests := []struct {
+		name         string
+		triggerValue string
+		header       map[string]string
+		want         string
+	}{
+		{name: "trigger header", triggerValue: "beta", header: map[string]string{"X-Canary": "beta"}, want: "canary"},
+		{name: "lowercase header name", triggerValue: "beta", header: map[string]string{"x-canary": "beta"}, want: "canary"},
+		{name: "other value", triggerValue: "beta", header: map[string]string{"X-Canary": "stable"}, want: "stable"},
+		{name: "without header", triggerValue: "beta", want: "stable"},
+		{name: "any value", header: map[string]string{"X-Canary": "anything"}, want: "canary"},
+		{name: "any value without header", want: "stable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			New(mux).AddMiddlewares(NewCanaryMiddleware(CanaryConfig{
+				TriggerHeader: "x-canary",
+				TriggerValue:  tt.triggerValue,
+				CanaryHandler: textHandler("canary"),
+			})).Get("/checkout", textHandler("stable"))
+
+			req := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Body.String() != tt.want {
+				t.Fatalf("handler = %q, want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("missing configuration", func(t *testing.T) {
+		assertPanics(t, "canary trigger header and handler are required", func() {
+			NewCanaryMiddleware(CanaryConfig{TriggerHeader: "X-Canary"})
+		})
+
+		assertPanics(t, "canary trigger header and handler are required", func() {
+			NewCanaryMiddleware(CanaryConfig{CanaryHandler: textHandler("canary")})
+		})
+	})
+}
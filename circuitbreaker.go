@@ -0,0 +1,126 @@
+package supermuxer
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitBreakerConfig configures the middleware created by NewCircuitBreakerMiddleware.
+type CircuitBreakerConfig struct {
+	// Threshold is the number of consecutive 5xx responses that opens the circuit. Defaults to 5.
+	Threshold int
+	// Timeout is how long the circuit stays open before a probe request is let through. Defaults to 30 seconds.
+	Timeout time.Duration
+	// OnOpen handles the requests rejected while the circuit is open. Defaults to 503.
+	OnOpen http.HandlerFunc
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker is the state machine of the middleware created by NewCircuitBreakerMiddleware.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	timeout   time.Duration
+	state     circuitState
+	failures  int
+	openedAt  time.Time
+}
+
+// allow reports whether a request can go through, moving an open circuit to half-open once the timeout passed.
+// While half-open, only the probe request goes through.
+func (cb *circuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if time.Since(cb.openedAt) < cb.timeout {
+			return false
+		}
+
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// record updates the state with the result of a request that went through.
+func (cb *circuitBreaker) record(failed bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if !failed {
+		cb.state = circuitClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = time.Now()
+	}
+}
+
+// NewCircuitBreakerMiddleware creates a middleware that fast-fails the requests while the next handler keeps failing.
+// After Threshold consecutive 5xx responses, or panics, the circuit opens and the requests are handled by OnOpen.
+// Once Timeout passes, one probe request goes through: the circuit closes if it succeeds, otherwise it opens again.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	breaker := supermuxer.NewCircuitBreakerMiddleware(supermuxer.CircuitBreakerConfig{Threshold: 3, Timeout: 10 * time.Second})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(breaker).Get("/inventory", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /inventory'
+//		responding with 503 for 10 seconds after 3 consecutive 5xx responses
+func NewCircuitBreakerMiddleware(cfg CircuitBreakerConfig) MiddlewareFunc {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 5
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	if cfg.OnOpen == nil {
+		cfg.OnOpen = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		}
+	}
+
+	cb := &circuitBreaker{threshold: cfg.Threshold, timeout: cfg.Timeout}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !cb.allow() {
+				cfg.OnOpen(w, r)
+				return
+			}
+
+			sw := newStatusWriter(w)
+			completed := false
+
+			defer func() {
+				cb.record(!completed || sw.Status() >= http.StatusInternalServerError)
+			}()
+
+			next(sw, r)
+			completed = true
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	var failing atomic.Bool
+	var calls atomic.Int32
+
+	handler := NewCircuitBreakerMiddleware(CircuitBreakerConfig{Threshold: 3, Timeout: 20 * time.Millisecond})(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	send := func() int {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/inventory", nil))
+		return rec.Code
+	}
+
+	failing.Store(true)
+	for i := range 3 {
+		if status := send(); status != http.StatusInternalServerError {
+			t.Fatalf("failure %d status = %d, want %d", i+1, status, http.StatusInternalServerError)
+		}
+	}
+
+	t.Run("closed to open", func(t *testing.T) {
+		if status := send(); status != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", status, http.StatusServiceUnavailable)
+		}
+
+		if calls.Load() != 3 {
+			t.Fatalf("handler calls = %d, want 3", calls.Load())
+		}
+	})
+
+	t.Run("failed probe opens again", func(t *testing.T) {
+		time.Sleep(30 * time.Millisecond)
+
+		if status := send(); status != http.StatusInternalServerError {
+			t.Fatalf("probe status = %d, want %d", status, http.StatusInternalServerError)
+		}
+
+		if status := send(); status != http.StatusServiceUnavailable {
+			t.Fatalf("status after the failed probe = %d, want %d", status, http.StatusServiceUnavailable)
+		}
+	})
+
+	t.Run("open to closed", func(t *testing.T) {
+		time.Sleep(30 * time.Millisecond)
+		failing.Store(false)
+
+		for i := range 3 {
+			if status := send(); status != http.StatusOK {
+				t.Fatalf("request %d status = %d, want %d", i+1, status, http.StatusOK)
+			}
+		}
+	})
+}
+
+func TestCircuitBreakerProbe(t *testing.T) {
+	const (
+		panicking = iota
+		blocking
+		healthy
+	)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var mode atomic.Int32
+
+	handler := NewCircuitBreakerMiddleware(CircuitBreakerConfig{Threshold: 1, Timeout: 10 * time.Millisecond})(func(w http.ResponseWriter, r *http.Request) {
+		switch mode.Load() {
+		case panicking:
+			panic("inventory unavailable")
+		case blocking:
+			close(started)
+			<-release
+		}
+	})
+
+	send := func() int {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/inventory", nil))
+		return rec.Code
+	}
+
+	// A panic counts as a failure, as the recovery middleware may only run after the circuit breaker.
+	func() {
+		defer func() { recover() }()
+		send()
+	}()
+
+	if status := send(); status != http.StatusServiceUnavailable {
+		t.Fatalf("status after the panic = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	mode.Store(blocking)
+
+	done := make(chan int)
+	go func() { done <- send() }()
+	<-started
+
+	if status := send(); status != http.StatusServiceUnavailable {
+		t.Fatalf("status during the probe = %d, want %d", status, http.StatusServiceUnavailable)
+	}
+
+	mode.Store(healthy)
+	close(release)
+	if status := <-done; status != http.StatusOK {
+		t.Fatalf("probe status = %d, want %d", status, http.StatusOK)
+	}
+
+	if status := send(); status != http.StatusOK {
+		t.Fatalf("status after the probe = %d, want %d", status, http.StatusOK)
+	}
+}
+
+func TestCircuitBreakerOnOpen(t *testing.T) {
+	handler := NewCircuitBreakerMiddleware(CircuitBreakerConfig{
+		Threshold: 1,
+		OnOpen:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) },
+	})(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusTeapot {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusTeapot)
+	}
+}
@@ -0,0 +1,117 @@
+package supermuxer
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// CompressionEncoder creates a writer compressing to w at the level, where zero is the default level of the
+// algorithm. It returns nil if the level is invalid.
+type CompressionEncoder func(w io.Writer, level int) io.WriteCloser
+
+// CompressionConfig configures the middleware created by NewCompressionMiddleware.
+type CompressionConfig struct {
+	// MinSize is the minimum size, in bytes, of the compressed responses. Defaults to DefaultGzipMinSize.
+	MinSize int
+	// Level is the compression level of the encoders. Zero is the default level of each algorithm.
+	Level int
+	// Algorithms lists the 'Content-Encoding' algorithms in order of preference. Defaults to gzip and deflate.
+	Algorithms []string
+	// Encoders adds or replaces the encoders of the algorithms. The gzip and deflate encoders are built in, while
+	// others like brotli ('br') must be added here, such as with the contrib/supermuxerbrotli module.
+	Encoders map[string]CompressionEncoder
+}
+
+var compressionEncoders = map[string]CompressionEncoder{
+	"gzip": func(w io.Writer, level int) io.WriteCloser {
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+
+		encoder, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil
+		}
+
+		return encoder
+	},
+	"deflate": func(w io.Writer, level int) io.WriteCloser {
+		if level == 0 {
+			level = flate.DefaultCompression
+		}
+
+		encoder, err := flate.NewWriter(w, level)
+		if err != nil {
+			return nil
+		}
+
+		return encoder
+	},
+}
+
+// NewCompressionMiddleware creates a middleware that compresses the responses of at least MinSize bytes with the
+// first algorithm of Algorithms accepted by the 'Accept-Encoding' request header.
+// It panics if an algorithm has no encoder or the level is invalid for one of them.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	compression := supermuxer.NewCompressionMiddleware(supermuxer.CompressionConfig{Algorithms: []string{"deflate", "gzip"}})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(compression).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		with a deflate response, or a gzip one for the clients that do not accept deflate
+func NewCompressionMiddleware(cfg CompressionConfig) MiddlewareFunc {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = DefaultGzipMinSize
+	}
+
+	if cfg.Algorithms == nil {
+		cfg.Algorithms = []string{"gzip", "deflate"}
+	}
+
+	encoders := make(map[string]func(io.Writer) io.WriteCloser, len(cfg.Algorithms))
+	for _, algorithm := range cfg.Algorithms {
+		encoder, ok := cfg.Encoders[algorithm]
+		if !ok {
+			encoder, ok = compressionEncoders[algorithm]
+		}
+
+		if !ok {
+			panic(fmt.Sprintf("supermuxer: no encoder for compression algorithm %q", algorithm))
+		}
+
+		if encoder(io.Discard, cfg.Level) == nil {
+			panic(fmt.Sprintf("supermuxer: invalid %s compression level %d", algorithm, cfg.Level))
+		}
+
+		encoders[algorithm] = func(w io.Writer) io.WriteCloser {
+			return encoder(w, cfg.Level)
+		}
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			for _, algorithm := range cfg.Algorithms {
+				if acceptsEncoding(r, algorithm) {
+					cw := newCompressWriter(w, algorithm, cfg.MinSize, encoders[algorithm])
+					next(cw, r)
+
+					// Not deferred, for the same reason as in NewGzipMiddlewareWithMinSize.
+					cw.Close()
+					return
+				}
+			}
+
+			next(w, r)
+		}
+	}
+}
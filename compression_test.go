@@ -0,0 +1,139 @@
+package supermuxer
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type upperEncoder struct{ w io.Writer }
+
+func (e upperEncoder) Write(b []byte) (int, error) { return e.w.Write(bytes.ToUpper(b)) }
+
+func (e upperEncoder) Close() error { return nil }
+
+func TestCompressionMiddleware(t *testing.T) {
+	large := strings.Repeat("supermuxer ", 200)
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"deflate": func(r io.Reader) (io.Reader, error) {
+			return flate.NewReader(r), nil
+		},
+	}
+
+	send := func(handler http.HandlerFunc, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("each encoding", func(t *testing.T) {
+		handler := NewCompressionMiddleware(CompressionConfig{})(textHandler(large))
+
+		for encoding, decode := range decoders {
+			rec := send(handler, encoding)
+			if got := rec.Header().Get("Content-Encoding"); got != encoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, encoding)
+			}
+
+			reader, err := decode(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			body, err := io.ReadAll(reader)
+			if err != nil || string(body) != large {
+				t.Fatalf("%s body = %d bytes, %v, want %d bytes", encoding, len(body), err, len(large))
+			}
+		}
+	})
+
+	priorities := []struct {
+		name           string
+		algorithms     []string
+		acceptEncoding string
+		want           string
+	}{
+		{name: "default order", acceptEncoding: "deflate, gzip", want: "gzip"},
+		{name: "configured order", algorithms: []string{"deflate", "gzip"}, acceptEncoding: "gzip, deflate", want: "deflate"},
+		{name: "first accepted", algorithms: []string{"deflate", "gzip"}, acceptEncoding: "gzip", want: "gzip"},
+		{name: "refused algorithm", algorithms: []string{"deflate", "gzip"}, acceptEncoding: "deflate;q=0, gzip", want: "gzip"},
+		{name: "wildcard", algorithms: []string{"deflate", "gzip"}, acceptEncoding: "*", want: "deflate"},
+		{name: "none accepted", acceptEncoding: "br", want: ""},
+	}
+
+	for _, tt := range priorities {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewCompressionMiddleware(CompressionConfig{Algorithms: tt.algorithms})(textHandler(large))
+
+			rec := send(handler, tt.acceptEncoding)
+			if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.want)
+			}
+
+			if rec.Header().Get("Vary") != "Accept-Encoding" {
+				t.Fatalf("Vary = %q, want %q", rec.Header().Get("Vary"), "Accept-Encoding")
+			}
+		})
+	}
+
+	t.Run("minimum size", func(t *testing.T) {
+		handler := NewCompressionMiddleware(CompressionConfig{MinSize: 100})(textHandler(large[:99]))
+
+		rec := send(handler, "gzip")
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != large[:99] {
+			t.Fatalf("response = %v %q, want the uncompressed body", rec.Header(), rec.Body.String())
+		}
+
+		handler = NewCompressionMiddleware(CompressionConfig{MinSize: 100})(textHandler(large[:100]))
+		if rec := send(handler, "gzip"); rec.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("Content-Encoding = %q, want %q", rec.Header().Get("Content-Encoding"), "gzip")
+		}
+	})
+
+	t.Run("custom encoder", func(t *testing.T) {
+		handler := NewCompressionMiddleware(CompressionConfig{
+			Algorithms: []string{"upper", "gzip"},
+			Encoders: map[string]CompressionEncoder{
+				"upper": func(w io.Writer, level int) io.WriteCloser { return upperEncoder{w} },
+			},
+		})(textHandler(large))
+
+		rec := send(handler, "gzip, upper")
+		if rec.Header().Get("Content-Encoding") != "upper" || rec.Body.String() != strings.ToUpper(large) {
+			t.Fatalf("response = %q %q, want the upper encoding", rec.Header().Get("Content-Encoding"), rec.Body.String()[:20])
+		}
+	})
+
+	t.Run("panicking handler", func(t *testing.T) {
+		handler := PanicRecovery(nil)(NewCompressionMiddleware(CompressionConfig{})(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("partial"))
+			panic("boom")
+		}))
+
+		if rec := send(handler, "deflate"); rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "partial") {
+			t.Fatalf("response = %d %q, want %d without the buffered body", rec.Code, rec.Body.String(), http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		assertPanics(t, `no encoder for compression algorithm "br"`, func() {
+			NewCompressionMiddleware(CompressionConfig{Algorithms: []string{"br"}})
+		})
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		assertPanics(t, "invalid gzip compression level 42", func() {
+			NewCompressionMiddleware(CompressionConfig{Level: 42})
+		})
+	})
+}
@@ -0,0 +1,29 @@
+package supermuxer
+
+import (
+	"context"
+	"net/http"
+)
+
+// NewContextInjectMiddleware creates a middleware that stores the value returned by valueFn for every request
+// in the request context under the key, before the next handler. Several values can be injected by adding
+// one middleware for each key.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	tenant := supermuxer.NewContextInjectMiddleware(tenantKey{}, func(r *http.Request) any { return r.Header.Get("X-Tenant") })
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(tenant).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		with the 'X-Tenant' header in the request context under tenantKey{}
+func NewContextInjectMiddleware(key any, valueFn func(*http.Request) any) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			next(w, r.WithContext(context.WithValue(r.Context(), key, valueFn(r))))
+		}
+	}
+}
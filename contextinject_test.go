@@ -0,0 +1,36 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type (
+	tenantKey  struct{}
+	versionKey struct{}
+)
+
+func TestContextInjectMiddleware(t *testing.T) {
+	var tenant, version any
+
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(
+		NewContextInjectMiddleware(tenantKey{}, func(r *http.Request) any { return r.Header.Get("X-Tenant") }),
+		NewContextInjectMiddleware(versionKey{}, func(r *http.Request) any { return r.PathValue("version") }),
+	).Get("/{version}/users", func(w http.ResponseWriter, r *http.Request) {
+		tenant, version = r.Context().Value(tenantKey{}), r.Context().Value(versionKey{})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/users", nil)
+	req.Header.Set("X-Tenant", "acme")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if tenant != "acme" {
+		t.Fatalf("tenant = %v, want %q", tenant, "acme")
+	}
+
+	if version != "v2" {
+		t.Fatalf("version = %v, want %q", version, "v2")
+	}
+}
@@ -0,0 +1,31 @@
+// Package supermuxerbrotli adds the brotli algorithm to the compression middleware of supermuxer,
+// keeping the supermuxer module free of third-party dependencies.
+package supermuxerbrotli
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/dbarbosadev/supermuxer"
+)
+
+// Encoder is the supermuxer.CompressionEncoder of brotli, for the 'br' algorithm.
+// Zero is brotli.DefaultCompression, and the levels outside 1 to brotli.BestCompression are invalid.
+//
+// Example:
+//
+//	compression := supermuxer.NewCompressionMiddleware(supermuxer.CompressionConfig{
+//		Algorithms: []string{"br", "gzip"},
+//		Encoders:   map[string]supermuxer.CompressionEncoder{"br": supermuxerbrotli.Encoder},
+//	})
+var Encoder supermuxer.CompressionEncoder = func(w io.Writer, level int) io.WriteCloser {
+	if level == 0 {
+		level = brotli.DefaultCompression
+	}
+
+	if level < 1 || level > brotli.BestCompression {
+		return nil
+	}
+
+	return brotli.NewWriterLevel(w, level)
+}
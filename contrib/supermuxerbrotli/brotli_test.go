@@ -0,0 +1,69 @@
+package supermuxerbrotli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/dbarbosadev/supermuxer"
+)
+
+func TestEncoder(t *testing.T) {
+	large := strings.Repeat("supermuxer ", 200)
+
+	handler := supermuxer.NewCompressionMiddleware(supermuxer.CompressionConfig{
+		Algorithms: []string{"br", "gzip"},
+		Encoders:   map[string]supermuxer.CompressionEncoder{"br": Encoder},
+	})(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(large))
+	})
+
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{acceptEncoding: "gzip, br", want: "br"},
+		{acceptEncoding: "br;q=0, gzip", want: "gzip"},
+		{acceptEncoding: "deflate", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.want {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.want)
+			}
+
+			if tt.want != "br" {
+				return
+			}
+
+			body, err := io.ReadAll(brotli.NewReader(rec.Body))
+			if err != nil || string(body) != large {
+				t.Fatalf("decompressed body = %d bytes, %v, want %d bytes", len(body), err, len(large))
+			}
+		})
+	}
+}
+
+func TestEncoderLevel(t *testing.T) {
+	for _, level := range []int{0, 1, brotli.BestCompression} {
+		if Encoder(io.Discard, level) == nil {
+			t.Fatalf("Encoder(level %d) = nil, want an encoder", level)
+		}
+	}
+
+	for _, level := range []int{-1, brotli.BestCompression + 1} {
+		if Encoder(io.Discard, level) != nil {
+			t.Fatalf("Encoder(level %d) != nil, want nil", level)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+module github.com/dbarbosadev/supermuxer/contrib/supermuxerlang
+
+go 1.26.0
+
+require (
+	github.com/dbarbosadev/supermuxer v0.0.0-20261014040106-05ac50de9c61
+	golang.org/x/text v0.42.0
+)
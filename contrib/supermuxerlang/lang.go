@@ -0,0 +1,64 @@
+// Package supermuxerlang adds the language negotiation middlewares to supermuxer, matching the languages
+// with golang.org/x/text/language, and keeping the supermuxer module free of third-party dependencies.
+package supermuxerlang
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dbarbosadev/supermuxer"
+	"golang.org/x/text/language"
+)
+
+type matchedLanguageContextKey struct{}
+
+// NewAcceptLanguageMiddleware creates a middleware that matches the 'Accept-Language' header of every request against
+// the supported languages with language.NewMatcher, which also matches the related languages, e.g. 'en-US' matches
+// 'en'. The supported language is stored in the request context, readable with MatchedLanguageFromContext,
+// and the first supported language is used when none matches. It panics if there are no supported languages.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	languages := supermuxerlang.NewAcceptLanguageMiddleware([]language.Tag{language.English, language.BrazilianPortuguese})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(languages).Get("/products", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /products'
+//		with the 'pt-BR' language for the 'Accept-Language: pt-PT, en;q=0.5' header
+func NewAcceptLanguageMiddleware(supported []language.Tag) supermuxer.MiddlewareFunc {
+	if len(supported) == 0 {
+		panic("supermuxerlang: supported languages are required")
+	}
+
+	matcher := language.NewMatcher(supported)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			tag := supported[0]
+
+			// Malformed headers are parsed as far as possible, and an empty list keeps the first supported language.
+			accepted, _, _ := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+			if len(accepted) > 0 {
+				if _, index, confidence := matcher.Match(accepted...); confidence != language.No {
+					tag = supported[index]
+				}
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), matchedLanguageContextKey{}, tag)))
+		}
+	}
+}
+
+// MatchedLanguageFromContext returns the language stored by the middleware created by NewAcceptLanguageMiddleware,
+// or language.Und if there is none.
+func MatchedLanguageFromContext(ctx context.Context) language.Tag {
+	tag, ok := ctx.Value(matchedLanguageContextKey{}).(language.Tag)
+	if !ok {
+		return language.Und
+	}
+
+	return tag
+}
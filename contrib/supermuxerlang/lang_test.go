@@ -0,0 +1,64 @@
+package supermuxerlang
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestAcceptLanguageMiddleware(t *testing.T) {
+	handler := NewAcceptLanguageMiddleware([]language.Tag{language.English, language.BrazilianPortuguese, language.French})(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(MatchedLanguageFromContext(r.Context()).String()))
+		},
+	)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "exact match", acceptLanguage: "pt-BR", want: "pt-BR"},
+		{name: "quality order", acceptLanguage: "en;q=0.5, fr", want: "fr"},
+		{name: "region sub-match", acceptLanguage: "en-US", want: "en"},
+		{name: "related language", acceptLanguage: "pt-PT, en;q=0.5", want: "pt-BR"},
+		{name: "fallback to the first supported", acceptLanguage: "ja", want: "en"},
+		{name: "malformed header", acceptLanguage: ";;;", want: "en"},
+		{name: "missing header", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/products", nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Body.String() != tt.want {
+				t.Fatalf("language = %q, want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("without middleware", func(t *testing.T) {
+		if got := MatchedLanguageFromContext(context.Background()); got != language.Und {
+			t.Fatalf("MatchedLanguageFromContext = %v, want %v", got, language.Und)
+		}
+	})
+
+	t.Run("no supported languages", func(t *testing.T) {
+		defer func() {
+			if got := recover(); got != "supermuxerlang: supported languages are required" {
+				t.Fatalf("panic = %v, want %q", got, "supermuxerlang: supported languages are required")
+			}
+		}()
+
+		NewAcceptLanguageMiddleware(nil)
+	})
+}
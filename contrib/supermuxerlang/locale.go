@@ -0,0 +1,128 @@
+package supermuxerlang
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/dbarbosadev/supermuxer"
+	"golang.org/x/text/language"
+)
+
+// LocaleConfig configures the middleware created by NewLocaleMiddleware.
+type LocaleConfig struct {
+	// SupportedLocales lists the BCP 47 language tags of the application, e.g. 'en-US' or 'pt'.
+	SupportedLocales []string
+	// DefaultLocale is the locale of the requests matching none of the supported locales.
+	// Defaults to the first supported locale.
+	DefaultLocale string
+	// QueryParam is the query parameter holding the locale. Empty disables it.
+	QueryParam string
+	// CookieName is the cookie holding the locale. Empty disables it.
+	CookieName string
+	// ContextKey is the request context key of the locale. Defaults to a package key.
+	ContextKey any
+}
+
+type localeContextKey struct{}
+
+// NewLocaleMiddleware creates a middleware that detects the locale of every request among the supported locales,
+// looking at the query parameter, the cookie and then the 'Accept-Language' header. The language tags are parsed
+// and matched with language.NewMatcher, so 'en_us' matches 'en-US' and 'pt-PT' matches 'pt-BR' when there is no
+// closer locale. The supported locale, as written in SupportedLocales, is stored in the request context under
+// ContextKey, readable with LocaleFromContext. It panics if a supported locale is not a valid language tag.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	locale := supermuxerlang.NewLocaleMiddleware(supermuxerlang.LocaleConfig{SupportedLocales: []string{"en", "pt-BR"}, QueryParam: "lang"})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(locale).Get("/products", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /products'
+//		with the 'pt-BR' locale for the 'Accept-Language: pt-PT, en;q=0.5' header
+func NewLocaleMiddleware(cfg LocaleConfig) supermuxer.MiddlewareFunc {
+	if cfg.DefaultLocale == "" && len(cfg.SupportedLocales) > 0 {
+		cfg.DefaultLocale = cfg.SupportedLocales[0]
+	}
+
+	if cfg.ContextKey == nil {
+		cfg.ContextKey = localeContextKey{}
+	}
+
+	tags := make([]language.Tag, len(cfg.SupportedLocales))
+	for i, locale := range cfg.SupportedLocales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			panic(fmt.Sprintf("supermuxerlang: invalid supported locale %q: %v", locale, err))
+		}
+
+		tags[i] = tag
+	}
+
+	matcher := language.NewMatcher(tags)
+
+	match := func(candidates ...language.Tag) (string, bool) {
+		if len(tags) == 0 || len(candidates) == 0 {
+			return "", false
+		}
+
+		if _, index, confidence := matcher.Match(candidates...); confidence != language.No {
+			return cfg.SupportedLocales[index], true
+		}
+
+		return "", false
+	}
+
+	matchValue := func(value string) (string, bool) {
+		tag, err := language.Parse(value)
+		if err != nil {
+			return "", false
+		}
+
+		return match(tag)
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			locale, ok := "", false
+
+			if cfg.QueryParam != "" {
+				if value := r.URL.Query().Get(cfg.QueryParam); value != "" {
+					locale, ok = matchValue(value)
+				}
+			}
+
+			if !ok && cfg.CookieName != "" {
+				if cookie, err := r.Cookie(cfg.CookieName); err == nil {
+					locale, ok = matchValue(cookie.Value)
+				}
+			}
+
+			if !ok {
+				// Malformed headers are parsed as far as possible, like in NewAcceptLanguageMiddleware.
+				accepted, _, _ := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+				locale, ok = match(accepted...)
+			}
+
+			if !ok {
+				locale = cfg.DefaultLocale
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), cfg.ContextKey, locale)))
+		}
+	}
+}
+
+// LocaleFromContext returns the locale stored under the key by the middleware created by NewLocaleMiddleware,
+// or an empty string if there is none. A nil key reads the locale stored under the default key.
+func LocaleFromContext(ctx context.Context, key any) string {
+	if key == nil {
+		key = localeContextKey{}
+	}
+
+	locale, _ := ctx.Value(key).(string)
+	return locale
+}
@@ -0,0 +1,89 @@
+package supermuxerlang
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	cfg := LocaleConfig{
+		SupportedLocales: []string{"en-US", "pt-BR", "fr"},
+		DefaultLocale:    "pt-BR",
+		QueryParam:       "lang",
+		CookieName:       "locale",
+	}
+
+	handler := NewLocaleMiddleware(cfg)(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(LocaleFromContext(r.Context(), nil)))
+	})
+
+	tests := []struct {
+		name           string
+		query          string
+		cookie         string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "query parameter", query: "fr", cookie: "en-US", acceptLanguage: "en-US", want: "fr"},
+		{name: "query parameter with underscore", query: "en_us", want: "en-US"},
+		{name: "cookie", cookie: "fr", acceptLanguage: "en-US", want: "fr"},
+		{name: "unsupported query parameter falls back to the cookie", query: "ja", cookie: "fr", want: "fr"},
+		{name: "invalid cookie falls back to the header", cookie: "not a locale", acceptLanguage: "fr-CA", want: "fr"},
+		{name: "header", acceptLanguage: "ja;q=0.9, en-GB;q=0.8", want: "en-US"},
+		{name: "header region match", acceptLanguage: "pt-PT", want: "pt-BR"},
+		{name: "default", acceptLanguage: "ja", want: "pt-BR"},
+		{name: "no sources", want: "pt-BR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/"
+			if tt.query != "" {
+				target += "?lang=" + tt.query
+			}
+
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "locale", Value: tt.cookie})
+			}
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Body.String() != tt.want {
+				t.Fatalf("locale = %q, want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("disabled sources", func(t *testing.T) {
+		type key struct{}
+		handler := NewLocaleMiddleware(LocaleConfig{SupportedLocales: []string{"en-US", "fr"}, ContextKey: key{}})(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(LocaleFromContext(r.Context(), key{})))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/?lang=fr", nil)
+		req.AddCookie(&http.Cookie{Name: "locale", Value: "fr"})
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Body.String() != "en-US" {
+			t.Fatalf("locale = %q, want the default %q", rec.Body.String(), "en-US")
+		}
+	})
+
+	t.Run("invalid supported locale", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("NewLocaleMiddleware did not panic")
+			}
+		}()
+
+		NewLocaleMiddleware(LocaleConfig{SupportedLocales: []string{"not a locale"}})
+	})
+}
@@ -0,0 +1,82 @@
+// Package supermuxerotel adds an OpenTelemetry tracing middleware to supermuxer,
+// keeping the supermuxer module free of third-party dependencies.
+package supermuxerotel
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/dbarbosadev/supermuxer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/dbarbosadev/supermuxer/contrib/supermuxerotel"
+
+// NewOpenTelemetryMiddleware creates a middleware that traces every request with a server span of the tracer provider,
+// named after the method and the registered pattern of the route, like 'GET /users/{id}'. The parent span is
+// extracted from the request headers, like 'traceparent' and 'tracestate', with otel.GetTextMapPropagator.
+// The span has the 'http.method', 'http.route', 'http.status_code' and 'net.peer.ip' attributes, and the error
+// status for 5xx responses. It is stored in the request context, so the next handlers can create child spans.
+// A nil tracer provider uses otel.GetTracerProvider.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxerotel.NewOpenTelemetryMiddleware(tracerProvider))
+//	superRouter.Get("/users/{id}", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users/{id}'
+//		with a 'GET /users/{id}' span for every request
+func NewOpenTelemetryMiddleware(tp trace.TracerProvider) supermuxer.MiddlewareFunc {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	tracer := tp.Tracer(tracerName)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			route := supermuxer.RoutePattern(r)
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			ctx, span := tracer.Start(ctx, r.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.method", r.Method),
+					attribute.String("http.route", route),
+					attribute.String("net.peer.ip", peerIP(r)),
+				),
+			)
+			defer span.End()
+
+			sw := supermuxer.NewStatusRecorder(w)
+
+			defer func() {
+				status := sw.Status()
+				span.SetAttributes(attribute.Int("http.status_code", status))
+				if status >= http.StatusInternalServerError {
+					span.SetStatus(codes.Error, http.StatusText(status))
+				}
+			}()
+
+			next(sw, r.WithContext(ctx))
+		}
+	}
+}
+
+// peerIP returns the IP address of the client connection.
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,113 @@
+package supermuxerotel
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dbarbosadev/supermuxer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOpenTelemetryMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var handlerSpan trace.SpanContext
+
+	serveMux := http.NewServeMux()
+	superRouter := supermuxer.New(serveMux)
+	superRouter.AddMiddlewares(NewOpenTelemetryMiddleware(tp))
+	superRouter.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		handlerSpan = trace.SpanContextFromContext(r.Context())
+		w.WriteHeader(http.StatusAccepted)
+	})
+	superRouter.Get("/failing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.RemoteAddr = "203.0.113.9:4312"
+	serveMux.ServeHTTP(httptest.NewRecorder(), req)
+	serveMux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/failing", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("ended spans = %d, want 2", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "GET /users/{id}" {
+		t.Fatalf("span name = %q, want %q", span.Name(), "GET /users/{id}")
+	}
+
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("span kind = %v, want %v", span.SpanKind(), trace.SpanKindServer)
+	}
+
+	if !span.SpanContext().Equal(handlerSpan) {
+		t.Fatal("the handler context does not hold the request span")
+	}
+
+	want := map[attribute.Key]attribute.Value{
+		"http.method":      attribute.StringValue(http.MethodGet),
+		"http.route":       attribute.StringValue("/users/{id}"),
+		"http.status_code": attribute.IntValue(http.StatusAccepted),
+		"net.peer.ip":      attribute.StringValue("203.0.113.9"),
+	}
+
+	got := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		got[attr.Key] = attr.Value
+	}
+
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("attribute %s = %v, want %v", key, got[key].Emit(), value.Emit())
+		}
+	}
+
+	if span.Status().Code != codes.Unset {
+		t.Fatalf("status = %v, want %v", span.Status().Code, codes.Unset)
+	}
+
+	if failing := spans[1]; failing.Status().Code != codes.Error {
+		t.Fatalf("5xx status = %v, want %v", failing.Status().Code, codes.Error)
+	}
+}
+
+func TestOpenTelemetryMiddlewareParentSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	// The global propagator is a no-op by default.
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	handler := NewOpenTelemetryMiddleware(tp)(func(w http.ResponseWriter, r *http.Request) {})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+
+	parent := spans[0].Parent()
+	if parent.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("parent = %s/%s, want the span of the traceparent header", parent.TraceID(), parent.SpanID())
+	}
+
+	if spans[0].SpanContext().TraceID() != parent.TraceID() {
+		t.Fatal("the span does not continue the trace of the parent")
+	}
+}
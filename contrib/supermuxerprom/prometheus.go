@@ -0,0 +1,92 @@
+// Package supermuxerprom adds a Prometheus metrics middleware to supermuxer,
+// keeping the supermuxer module free of third-party dependencies.
+package supermuxerprom
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dbarbosadev/supermuxer"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewPrometheusMiddleware creates a middleware that collects the metrics of the requests in the registerer:
+//   - http_requests_total: counter of the requests, by method, path and status.
+//   - http_request_duration_seconds: histogram of the request durations, by method and path.
+//   - http_requests_in_flight: gauge of the requests being handled, by method.
+//
+// The path is the registered pattern of the route, like '/users/{id}', so the metrics stay at low cardinality.
+// A nil registerer uses prometheus.DefaultRegisterer, and the metrics already registered in it are reused.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxerprom.NewPrometheusMiddleware(prometheus.DefaultRegisterer))
+//	superRouter.Get("/users/{id}", handler)
+//	serveMux.Handle("GET /metrics", promhttp.Handler())
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users/{id}'
+//		with its metrics exposed on 'GET /metrics'
+func NewPrometheusMiddleware(reg prometheus.Registerer) supermuxer.MiddlewareFunc {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	requests := register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests.",
+	}, []string{"method", "path", "status"}))
+
+	durations := register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of the HTTP requests in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path"}))
+
+	inFlight := register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "Number of HTTP requests being handled.",
+	}, []string{"method"}))
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			gauge := inFlight.WithLabelValues(r.Method)
+			gauge.Inc()
+
+			sw := supermuxer.NewStatusRecorder(w)
+
+			defer func() {
+				gauge.Dec()
+
+				status := sw.Status()
+				path := supermuxer.RoutePattern(r)
+				requests.WithLabelValues(r.Method, path, strconv.Itoa(status)).Inc()
+				durations.WithLabelValues(r.Method, path).Observe(time.Since(start).Seconds())
+			}()
+
+			next(sw, r)
+		}
+	}
+}
+
+// register registers the collector, or returns the one already registered with the same description.
+func register[T prometheus.Collector](reg prometheus.Registerer, collector T) T {
+	if err := reg.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+
+		panic(err)
+	}
+
+	return collector
+}
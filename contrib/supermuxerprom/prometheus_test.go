@@ -0,0 +1,98 @@
+package supermuxerprom
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dbarbosadev/supermuxer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPrometheusMiddleware(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	var inFlight float64
+
+	serveMux := http.NewServeMux()
+	superRouter := supermuxer.New(serveMux)
+	superRouter.AddMiddlewares(NewPrometheusMiddleware(reg))
+	superRouter.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		inFlight = gatheredValue(t, reg, "http_requests_in_flight")
+		w.Write([]byte(r.PathValue("id")))
+	})
+	superRouter.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	for _, target := range []string{"/users/1", "/users/2"} {
+		serveMux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	serveMux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	if inFlight != 1 {
+		t.Fatalf("in-flight requests during the request = %v, want 1", inFlight)
+	}
+
+	expected := `
+# HELP http_requests_total Number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/users/{id}",status="200"} 2
+http_requests_total{method="POST",path="/users",status="201"} 1
+# HELP http_requests_in_flight Number of HTTP requests being handled.
+# TYPE http_requests_in_flight gauge
+http_requests_in_flight{method="GET"} 0
+http_requests_in_flight{method="POST"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "http_requests_total", "http_requests_in_flight"); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := testutil.CollectAndCount(reg, "http_request_duration_seconds"); count != 2 {
+		t.Fatalf("duration series = %d, want 2", count)
+	}
+}
+
+func TestPrometheusMiddlewareReusesCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	first := NewPrometheusMiddleware(reg)
+	second := NewPrometheusMiddleware(reg)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	for _, middleware := range []supermuxer.MiddlewareFunc{first, second} {
+		middleware(handler)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}
+
+	expected := `
+# HELP http_requests_total Number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="",status="200"} 2
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected), "http_requests_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// gatheredValue returns the sum of the values of the gauge or counter metric family gathered from reg.
+func gatheredValue(t *testing.T, reg *prometheus.Registry, name string) float64 {
+	t.Helper()
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := 0.0
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			total += metric.GetGauge().GetValue() + metric.GetCounter().GetValue()
+		}
+	}
+
+	return total
+}
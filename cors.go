@@ -0,0 +1,120 @@
+package supermuxer
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CORSConfig configures the middleware created by NewCORSMiddleware.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the routes. '*' allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods lists the methods allowed in preflight requests.
+	// When empty, GET, HEAD, POST, PUT, PATCH and DELETE are allowed.
+	AllowedMethods []string
+	// AllowedHeaders lists the headers allowed in preflight requests.
+	// When empty, the headers requested by the preflight request are allowed.
+	AllowedHeaders []string
+	// AllowCredentials allows requests with cookies or HTTP authentication. It cannot be combined with the '*'
+	// origin, which would let any site make credentialed requests and read their responses.
+	AllowCredentials bool
+	// MaxAge is the duration, in seconds, browsers may cache a preflight response. 0 omits the header.
+	MaxAge int
+}
+
+var defaultCORSMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// NewCORSMiddleware creates a middleware that adds the cross-origin resource sharing headers to the responses
+// of allowed origins and answers preflight 'OPTIONS' requests with 204. Preflight requests from other origins get 403.
+// Preflight requests only reach the middleware for routes that accept 'OPTIONS', e.g. registered with Options or Any,
+// unless the middleware wraps the whole router. It panics if AllowCredentials is set with the '*' origin.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	cors := supermuxer.NewCORSMiddleware(supermuxer.CORSConfig{AllowedOrigins: []string{"https://example.com"}})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(cors).Any("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the requests for the endpoint '/users' from 'https://example.com'
+//		with the CORS headers
+func NewCORSMiddleware(cfg CORSConfig) MiddlewareFunc {
+	allowAnyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	if allowAnyOrigin && cfg.AllowCredentials {
+		panic("supermuxer: CORS credentials cannot be allowed for any origin")
+	}
+
+	allowedMethods := cfg.AllowedMethods
+	if len(allowedMethods) == 0 {
+		allowedMethods = defaultCORSMethods
+	}
+	methods := strings.Join(allowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			if origin == "" {
+				next(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Add("Vary", "Origin")
+
+			if !allowAnyOrigin && !slices.Contains(cfg.AllowedOrigins, origin) {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+
+				next(w, r)
+				return
+			}
+
+			if allowAnyOrigin {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if cfg.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				next(w, r)
+				return
+			}
+
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", methods)
+
+			if headers != "" {
+				header.Set("Access-Control-Allow-Headers", headers)
+			} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				header.Set("Access-Control-Allow-Headers", requested)
+			}
+
+			if cfg.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
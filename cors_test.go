@@ -0,0 +1,118 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	newHandler := func(cfg CORSConfig) http.Handler {
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(NewCORSMiddleware(cfg)).Any("/users", textHandler("users"))
+
+		return mux
+	}
+
+	request := func(method string, origin string, headers map[string]string) *http.Request {
+		req := httptest.NewRequest(method, "/users", nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		return req
+	}
+
+	preflightHeaders := map[string]string{"Access-Control-Request-Method": http.MethodPut, "Access-Control-Request-Headers": "X-Token"}
+
+	t.Run("preflight", func(t *testing.T) {
+		handler := newHandler(CORSConfig{AllowedOrigins: []string{"https://example.com"}, MaxAge: 600})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, request(http.MethodOptions, "https://example.com", preflightHeaders))
+
+		want := map[string]string{
+			"Access-Control-Allow-Origin":  "https://example.com",
+			"Access-Control-Allow-Methods": "GET, HEAD, POST, PUT, PATCH, DELETE",
+			"Access-Control-Allow-Headers": "X-Token",
+			"Access-Control-Max-Age":       "600",
+		}
+
+		if rec.Code != http.StatusNoContent || rec.Body.Len() != 0 {
+			t.Fatalf("response = %d %q, want 204 without a body", rec.Code, rec.Body.String())
+		}
+
+		for name, value := range want {
+			if got := rec.Header().Get(name); got != value {
+				t.Errorf("%s = %q, want %q", name, got, value)
+			}
+		}
+	})
+
+	t.Run("preflight from another origin", func(t *testing.T) {
+		handler := newHandler(CORSConfig{AllowedOrigins: []string{"https://example.com"}})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, request(http.MethodOptions, "https://evil.example", preflightHeaders))
+
+		if rec.Code != http.StatusForbidden || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("response = %d %v, want 403 without CORS headers", rec.Code, rec.Header())
+		}
+	})
+
+	t.Run("request from another origin", func(t *testing.T) {
+		handler := newHandler(CORSConfig{AllowedOrigins: []string{"https://example.com"}})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, request(http.MethodGet, "https://evil.example", nil))
+
+		if rec.Body.String() != "users" || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Fatalf("response = %q %v, want the handler response without CORS headers", rec.Body.String(), rec.Header())
+		}
+	})
+
+	t.Run("wildcard origin", func(t *testing.T) {
+		handler := newHandler(CORSConfig{AllowedOrigins: []string{"*"}})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, request(http.MethodGet, "https://any.example", nil))
+
+		if rec.Body.String() != "users" || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Fatalf("response = %q %v, want the handler response with 'Access-Control-Allow-Origin: *'", rec.Body.String(), rec.Header())
+		}
+
+		if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+			t.Fatal("credentials allowed for the wildcard origin")
+		}
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		handler := newHandler(CORSConfig{AllowedOrigins: []string{"https://example.com"}, AllowCredentials: true})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, request(http.MethodGet, "https://example.com", map[string]string{"Cookie": "session=1"}))
+
+		if rec.Header().Get("Access-Control-Allow-Origin") != "https://example.com" || rec.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Fatalf("headers = %v, want the origin and the credentials allowed", rec.Header())
+		}
+
+		if rec.Header().Get("Vary") != "Origin" {
+			t.Fatalf("Vary = %q, want %q", rec.Header().Get("Vary"), "Origin")
+		}
+	})
+
+	t.Run("credentials with the wildcard origin", func(t *testing.T) {
+		assertPanics(t, "CORS credentials cannot be allowed for any origin", func() {
+			NewCORSMiddleware(CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+		})
+	})
+
+	t.Run("same origin", func(t *testing.T) {
+		handler := newHandler(CORSConfig{AllowedOrigins: []string{"https://example.com"}})
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, request(http.MethodGet, "", nil))
+
+		if rec.Body.String() != "users" || len(rec.Header().Values("Vary")) != 0 {
+			t.Fatalf("response = %q %v, want the handler response unchanged", rec.Body.String(), rec.Header())
+		}
+	})
+}
@@ -0,0 +1,132 @@
+package supermuxer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CSRFConfig configures the middleware created by NewCSRFMiddleware.
+type CSRFConfig struct {
+	// TokenLength is the number of random bytes of the token. Defaults to 32.
+	TokenLength int
+	// CookieName is the cookie holding the token. Defaults to 'csrf_token'.
+	CookieName string
+	// HeaderName is the request header that must repeat the token. Defaults to 'X-CSRF-Token'.
+	HeaderName string
+	// ExcludedMethods lists the methods that are not validated. Defaults to GET, HEAD, OPTIONS and TRACE.
+	ExcludedMethods []string
+	// SecretKey signs the tokens with HMAC-SHA256, so they cannot be forged. It is required.
+	SecretKey []byte
+}
+
+type csrfTokenContextKey struct{}
+
+// NewCSRFMiddleware creates a middleware that protects against cross-site request forgery with signed tokens.
+// Every client gets a token, signed with the secret key and stored in a cookie, that the requests with
+// state-mutating methods must repeat in the header. Requests with a missing or invalid token get 403.
+// The token is stored in the request context, readable with CSRFToken, to be rendered in forms or pages.
+// It panics if the secret key is empty.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	csrf := supermuxer.NewCSRFMiddleware(supermuxer.CSRFConfig{SecretKey: secret})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(csrf).Post("/transfer", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /transfer'
+//		responding with 403 when the 'X-CSRF-Token' header does not match the 'csrf_token' cookie
+func NewCSRFMiddleware(cfg CSRFConfig) MiddlewareFunc {
+	if len(cfg.SecretKey) == 0 {
+		panic("supermuxer: CSRF secret key is required")
+	}
+
+	if cfg.TokenLength <= 0 {
+		cfg.TokenLength = 32
+	}
+
+	if cfg.CookieName == "" {
+		cfg.CookieName = "csrf_token"
+	}
+
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = "X-CSRF-Token"
+	}
+
+	if cfg.ExcludedMethods == nil {
+		cfg.ExcludedMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace}
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token := ""
+			if cookie, err := r.Cookie(cfg.CookieName); err == nil && validCSRFToken(cookie.Value, cfg.SecretKey) {
+				token = cookie.Value
+			}
+
+			if !slices.Contains(cfg.ExcludedMethods, r.Method) {
+				sent := r.Header.Get(cfg.HeaderName)
+				if token == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+					http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+					return
+				}
+			}
+
+			if token == "" {
+				token = newCSRFToken(cfg.TokenLength, cfg.SecretKey)
+				http.SetCookie(w, &http.Cookie{
+					Name:     cfg.CookieName,
+					Value:    token,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), csrfTokenContextKey{}, token)))
+		}
+	}
+}
+
+// CSRFToken returns the CSRF token of the request stored by the middleware created by NewCSRFMiddleware,
+// or an empty string if there is none.
+func CSRFToken(r *http.Request) string {
+	token, _ := r.Context().Value(csrfTokenContextKey{}).(string)
+	return token
+}
+
+// newCSRFToken creates a token made of a random nonce and its signature.
+func newCSRFToken(length int, secret []byte) string {
+	nonce := make([]byte, length)
+	rand.Read(nonce)
+
+	encoded := base64.RawURLEncoding.EncodeToString(nonce)
+	return encoded + "." + signCSRFNonce(encoded, secret)
+}
+
+func signCSRFNonce(nonce string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(nonce))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// validCSRFToken reports whether the token was signed with the secret.
+func validCSRFToken(token string, secret []byte) bool {
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(signCSRFNonce(nonce, secret)))
+}
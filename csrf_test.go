@@ -0,0 +1,77 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	secret := []byte("csrf-secret")
+	handler := NewCSRFMiddleware(CSRFConfig{SecretKey: secret})(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(CSRFToken(r)))
+	})
+
+	get := httptest.NewRecorder()
+	handler(get, httptest.NewRequest(http.MethodGet, "/form", nil))
+
+	cookies := get.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "csrf_token" || !cookies[0].HttpOnly {
+		t.Fatalf("cookies = %v, want an HttpOnly csrf_token cookie", cookies)
+	}
+
+	token := cookies[0].Value
+	if get.Body.String() != token {
+		t.Fatalf("context token = %q, want the cookie token %q", get.Body.String(), token)
+	}
+
+	post := func(cookie string, header string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/transfer", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "csrf_token", Value: cookie})
+		}
+
+		if header != "" {
+			req.Header.Set("X-CSRF-Token", header)
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		return rec
+	}
+
+	forged := newCSRFToken(32, []byte("other-secret"))
+	tampered := token[:len(token)-1] + "A"
+	if tampered == token {
+		tampered = token[:len(token)-1] + "B"
+	}
+
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		status int
+	}{
+		{name: "valid token", cookie: token, header: token, status: http.StatusOK},
+		{name: "missing token", status: http.StatusForbidden},
+		{name: "missing header", cookie: token, status: http.StatusForbidden},
+		{name: "different header", cookie: token, header: newCSRFToken(32, secret), status: http.StatusForbidden},
+		{name: "tampered token", cookie: tampered, header: tampered, status: http.StatusForbidden},
+		{name: "forged token", cookie: forged, header: forged, status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := post(tt.cookie, tt.header); rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+
+	t.Run("missing secret", func(t *testing.T) {
+		assertPanics(t, "CSRF secret key is required", func() {
+			NewCSRFMiddleware(CSRFConfig{})
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package supermuxer
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewDeprecationMiddleware creates a middleware that marks the routes as deprecated, following RFC 8594, with the
+// 'Deprecation: true', 'Sunset: <date>' and 'Link: <link>; rel="sunset"' headers on every response.
+// An empty link skips the 'Link' header.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	deprecation := supermuxer.NewDeprecationMiddleware(sunset, "https://example.com/docs/v2-migration")
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.SubGroup("/v1").AddMiddlewares(deprecation).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /v1/users'
+//		with the deprecation headers
+func NewDeprecationMiddleware(sunset time.Time, link string) MiddlewareFunc {
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("Deprecation", "true")
+			header.Set("Sunset", sunsetDate)
+
+			if link != "" {
+				header.Add("Link", "<"+link+`>; rel="sunset"`)
+			}
+
+			next(w, r)
+		}
+	}
+}
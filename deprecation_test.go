@@ -0,0 +1,57 @@
+package supermuxer
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	sunset := time.Date(2027, time.March, 31, 23, 59, 59, 0, time.FixedZone("WEST", 3600))
+
+	tests := []struct {
+		name string
+		link string
+		want []string
+	}{
+		{name: "with link", link: "https://example.com/docs/v2-migration", want: []string{`<https://example.com/docs/v2-migration>; rel="sunset"`}},
+		{name: "without link"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			New(mux).SubGroup("/v1").AddMiddlewares(NewDeprecationMiddleware(sunset, tt.link)).Get("/users", textHandler("users"))
+
+			rec := serve(mux, http.MethodGet, "/v1/users")
+			if rec.Body.String() != "users" {
+				t.Fatalf("body = %q, want %q", rec.Body.String(), "users")
+			}
+
+			header := rec.Header()
+			if got := header.Get("Deprecation"); got != "true" {
+				t.Fatalf("Deprecation = %q, want %q", got, "true")
+			}
+
+			if got, want := header.Get("Sunset"), "Wed, 31 Mar 2027 22:59:59 GMT"; got != want {
+				t.Fatalf("Sunset = %q, want %q", got, want)
+			}
+
+			if got := header.Values("Link"); len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Fatalf("Link = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("keeps other links", func(t *testing.T) {
+		handler := NewDeprecationMiddleware(sunset, "https://example.com/sunset")(textHandler("ok"))
+		wrapped := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Link", `</users?page=2>; rel="next"`)
+			handler(w, r)
+		}
+
+		if got := serve(http.HandlerFunc(wrapped), http.MethodGet, "/").Header().Values("Link"); len(got) != 2 {
+			t.Fatalf("Link = %q, want the next and sunset links", got)
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package supermuxer
+
+import (
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func (r *router) ServeDownload(path string, filePath string, contentType string) *router {
+	return r.Get(path, func(w http.ResponseWriter, req *http.Request) {
+		file, err := os.Open(filePath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				http.NotFound(w, req)
+				return
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil || info.IsDir() {
+			http.NotFound(w, req)
+			return
+		}
+
+		name := filepath.Base(filePath)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+
+		http.ServeContent(w, req, name, info.ModTime(), file)
+	})
+}
@@ -0,0 +1,93 @@
+package supermuxer
+
+import (
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestServeDownload(t *testing.T) {
+	dir := t.TempDir()
+	content := "id,name\n1,supermuxer\n"
+
+	for _, name := range []string{"report.csv", "relatório 2026.csv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	superRouter := New(mux)
+	superRouter.ServeDownload("/report", filepath.Join(dir, "report.csv"), "text/csv")
+	superRouter.ServeDownload("/relatorio", filepath.Join(dir, "relatório 2026.csv"), "")
+	superRouter.ServeDownload("/missing", filepath.Join(dir, "missing.csv"), "text/csv")
+	superRouter.ServeDownload("/directory", dir, "")
+
+	tests := []struct {
+		name        string
+		path        string
+		disposition string
+		filename    string
+		contentType string
+	}{
+		{name: "configured content type", path: "/report", disposition: "attachment; filename=report.csv",
+			filename: "report.csv", contentType: "text/csv"},
+		{name: "detected content type and UTF-8 filename", path: "/relatorio", disposition: "attachment; filename*=utf-8''relat%C3%B3rio%202026.csv",
+			filename: "relatório 2026.csv", contentType: "text/csv; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(mux, http.MethodGet, tt.path)
+			if rec.Code != http.StatusOK || rec.Body.String() != content {
+				t.Fatalf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusOK, content)
+			}
+
+			header := rec.Header()
+			if got := header.Get("Content-Disposition"); got != tt.disposition {
+				t.Fatalf("Content-Disposition = %q, want %q", got, tt.disposition)
+			}
+
+			disposition, params, err := mime.ParseMediaType(header.Get("Content-Disposition"))
+			if err != nil || disposition != "attachment" || params["filename"] != tt.filename {
+				t.Fatalf("Content-Disposition = %q, want an attachment named %q", header.Get("Content-Disposition"), tt.filename)
+			}
+
+			if got := header.Get("Content-Type"); got != tt.contentType {
+				t.Fatalf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+
+			if got := header.Get("Content-Length"); got != strconv.Itoa(len(content)) {
+				t.Fatalf("Content-Length = %q, want %q", got, strconv.Itoa(len(content)))
+			}
+
+			if header.Get("Last-Modified") == "" {
+				t.Fatal("Last-Modified is missing")
+			}
+		})
+	}
+
+	t.Run("range request", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/report", nil)
+		req.Header.Set("Range", "bytes=0-6")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusPartialContent || rec.Body.String() != content[:7] {
+			t.Fatalf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusPartialContent, content[:7])
+		}
+	})
+
+	for _, path := range []string{"/missing", "/directory"} {
+		t.Run(path, func(t *testing.T) {
+			if rec := serve(mux, http.MethodGet, path); rec.Code != http.StatusNotFound {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
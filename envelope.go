@@ -0,0 +1,90 @@
+package supermuxer
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+)
+
+// EnvelopeConfig configures the middleware created by NewResponseEnvelopeMiddleware.
+type EnvelopeConfig struct {
+	// DataKey is the envelope key of the body of the 2xx responses. Defaults to 'data'.
+	DataKey string
+	// MetaKey is the envelope key of the response metadata. Defaults to 'meta'.
+	MetaKey string
+	// ErrorKey is the envelope key of the body of the other responses. Defaults to 'error'.
+	ErrorKey string
+	// ExcludedPaths lists the request paths whose responses are not wrapped.
+	ExcludedPaths []string
+}
+
+// NewResponseEnvelopeMiddleware creates a middleware that wraps the JSON responses of the next handler in the
+// '{"data": ..., "meta": {...}, "error": ...}' envelope. The body of the 2xx responses goes under DataKey and the
+// body of the others under ErrorKey, leaving the other key null. The metadata holds the status code, the method and
+// the path of the request, and its ID when set by the middleware created by NewRequestIDMiddleware.
+// Bodies that are not JSON are wrapped as strings.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	envelope := supermuxer.NewResponseEnvelopeMiddleware(supermuxer.EnvelopeConfig{ExcludedPaths: []string{"/healthz"}})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(envelope).Get("/users/{id}", handler)
+//
+//	# Result: supermuxer configuration to handle the request 'GET /users/42' with the response
+//		'{"data": {"id": 42}, "error": null, "meta": {"method": "GET", "path": "/users/42", "status": 200}}'
+func NewResponseEnvelopeMiddleware(cfg EnvelopeConfig) MiddlewareFunc {
+	if cfg.DataKey == "" {
+		cfg.DataKey = "data"
+	}
+
+	if cfg.MetaKey == "" {
+		cfg.MetaKey = "meta"
+	}
+
+	if cfg.ErrorKey == "" {
+		cfg.ErrorKey = "error"
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(cfg.ExcludedPaths, r.URL.Path) {
+				next(w, r)
+				return
+			}
+
+			bw := newBufferWriter(w)
+			next(bw, r)
+
+			var body any
+			if bw.body.Len() > 0 {
+				if err := json.Unmarshal(bw.body.Bytes(), &body); err != nil {
+					body = bw.body.String()
+				}
+			}
+
+			status := bw.Status()
+			meta := map[string]any{
+				"status": status,
+				"method": r.Method,
+				"path":   r.URL.Path,
+			}
+
+			if id := RequestIDFromContext(r.Context()); id != "" {
+				meta["request_id"] = id
+			}
+
+			envelope := map[string]any{cfg.DataKey: nil, cfg.MetaKey: meta, cfg.ErrorKey: nil}
+			if status >= 200 && status < 300 {
+				envelope[cfg.DataKey] = body
+			} else {
+				envelope[cfg.ErrorKey] = body
+			}
+
+			w.Header().Del("Content-Length")
+			writeJSON(w, status, envelope)
+		}
+	}
+}
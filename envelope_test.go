@@ -0,0 +1,102 @@
+package supermuxer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestResponseEnvelopeMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(
+		NewRequestIDMiddleware(RequestIDOptions{}),
+		NewResponseEnvelopeMiddleware(EnvelopeConfig{ExcludedPaths: []string{"/health"}}),
+	)
+	superRouter.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "25")
+		writeJSON(w, http.StatusOK, map[string]any{"id": r.PathValue("id"), "name": "alice"})
+	})
+	superRouter.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "name is required"})
+	})
+	superRouter.Get("/text", textHandler("plain text"))
+	superRouter.Get("/health", textHandler("ok"))
+
+	send := func(method, target string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("X-Request-ID", "req-1")
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		var envelope map[string]any
+		json.Unmarshal(rec.Body.Bytes(), &envelope)
+		return rec, envelope
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+		want   map[string]any
+	}{
+		{name: "success", method: http.MethodGet, target: "/users/7", status: http.StatusOK, want: map[string]any{
+			"data":  map[string]any{"id": "7", "name": "alice"},
+			"meta":  map[string]any{"status": 200.0, "method": "GET", "path": "/users/7", "request_id": "req-1"},
+			"error": nil,
+		}},
+		{name: "error", method: http.MethodPost, target: "/users", status: http.StatusUnprocessableEntity, want: map[string]any{
+			"data":  nil,
+			"meta":  map[string]any{"status": 422.0, "method": "POST", "path": "/users", "request_id": "req-1"},
+			"error": map[string]any{"message": "name is required"},
+		}},
+		{name: "not JSON", method: http.MethodGet, target: "/text", status: http.StatusOK, want: map[string]any{
+			"data":  "plain text",
+			"meta":  map[string]any{"status": 200.0, "method": "GET", "path": "/text", "request_id": "req-1"},
+			"error": nil,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, envelope := send(tt.method, tt.target)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if rec.Header().Get("Content-Type") != "application/json" {
+				t.Fatalf("Content-Type = %q, want %q", rec.Header().Get("Content-Type"), "application/json")
+			}
+
+			if !reflect.DeepEqual(envelope, tt.want) {
+				t.Fatalf("envelope = %v, want %v", envelope, tt.want)
+			}
+		})
+	}
+
+	t.Run("excluded path", func(t *testing.T) {
+		if rec, _ := send(http.MethodGet, "/health"); rec.Body.String() != "ok" {
+			t.Fatalf("body = %q, want %q", rec.Body.String(), "ok")
+		}
+	})
+
+	t.Run("custom keys", func(t *testing.T) {
+		handler := NewResponseEnvelopeMiddleware(EnvelopeConfig{DataKey: "result", MetaKey: "info", ErrorKey: "problem"})(textHandler(`[1,2]`))
+
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/numbers", nil))
+
+		var envelope map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &envelope); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok := envelope["problem"]; !ok || envelope["info"] == nil || !reflect.DeepEqual(envelope["result"], []any{1.0, 2.0}) {
+			t.Fatalf("envelope = %v, want the result, info and problem keys", envelope)
+		}
+	})
+}
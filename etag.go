@@ -0,0 +1,79 @@
+package supermuxer
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+)
+
+// NewETagMiddleware creates a middleware that sets the 'ETag' header of successful 'GET' and 'HEAD' responses
+// to a hash of their body, unless the handler set one, and responds with 304 Not Modified when it matches
+// the 'If-None-Match' request header. The responses are buffered to compute the hash.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewETagMiddleware())
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		responding with 304 when the client already has the same response
+func NewETagMiddleware() MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next(w, r)
+				return
+			}
+
+			bw := newBufferWriter(w)
+			next(bw, r)
+
+			status := bw.Status()
+			if status < http.StatusOK || status >= http.StatusMultipleChoices {
+				bw.flush()
+				return
+			}
+
+			etag := w.Header().Get("ETag")
+			if etag == "" {
+				hash := fnv.New64a()
+				hash.Write(bw.body.Bytes())
+				etag = fmt.Sprintf(`"%x"`, hash.Sum64())
+				w.Header().Set("ETag", etag)
+			}
+
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				header := w.Header()
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			bw.flush()
+		}
+	}
+}
+
+// etagMatches reports whether the 'If-None-Match' header matches the ETag, using the weak comparison.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
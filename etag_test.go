@@ -0,0 +1,74 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestETagMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(NewETagMiddleware())
+	superRouter.Get("/users", textHandler("users"))
+	superRouter.Get("/versioned", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+		w.Write([]byte("versioned"))
+	})
+	superRouter.Get("/missing", http.NotFound)
+	superRouter.Post("/users", textHandler("created"))
+
+	request := func(method string, target string, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	first := request(http.MethodGet, "/users", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Body.String() != "users" || etag == "" {
+		t.Fatalf("first response = %d %q with ETag %q, want 200 with the body and an ETag", first.Code, first.Body.String(), etag)
+	}
+
+	second := request(http.MethodGet, "/users", etag)
+	if second.Code != http.StatusNotModified || second.Body.Len() != 0 || second.Header().Get("ETag") != etag {
+		t.Fatalf("second response = %d %q, want 304 without a body and with the ETag", second.Code, second.Body.String())
+	}
+
+	for _, ifNoneMatch := range []string{`"other", W/` + etag, "*"} {
+		if rec := request(http.MethodGet, "/users", ifNoneMatch); rec.Code != http.StatusNotModified {
+			t.Errorf("If-None-Match %q status = %d, want %d", ifNoneMatch, rec.Code, http.StatusNotModified)
+		}
+	}
+
+	if rec := request(http.MethodGet, "/users", `"other"`); rec.Code != http.StatusOK || rec.Body.String() != "users" {
+		t.Fatalf("response for another ETag = %d %q, want 200 with the body", rec.Code, rec.Body.String())
+	}
+
+	if rec := request(http.MethodGet, "/versioned", `"v2"`); rec.Code != http.StatusNotModified {
+		t.Fatalf("status for the handler ETag = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+
+	if rec := request(http.MethodGet, "/missing", ""); rec.Code != http.StatusNotFound || rec.Header().Get("ETag") != "" {
+		t.Fatalf("error response = %d with ETag %q, want 404 without an ETag", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	if rec := request(http.MethodPost, "/users", ""); rec.Header().Get("ETag") != "" {
+		t.Fatalf("POST ETag = %q, want none", rec.Header().Get("ETag"))
+	}
+
+	superRouter.WithAutoHEAD(true).Get("/orders", textHandler("orders"))
+	getETag := request(http.MethodGet, "/orders", "").Header().Get("ETag")
+	if headETag := request(http.MethodHead, "/orders", "").Header().Get("ETag"); headETag != getETag {
+		t.Fatalf("HEAD ETag = %q, want the GET one %q", headETag, getETag)
+	}
+
+	if rec := request(http.MethodHead, "/orders", getETag); rec.Code != http.StatusNotModified {
+		t.Fatalf("HEAD status for the GET ETag = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+}
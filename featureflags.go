@@ -0,0 +1,52 @@
+package supermuxer
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// FeatureFlagProvider returns the feature flags of the requests for NewFeatureFlagMiddleware.
+type FeatureFlagProvider interface {
+	Flags(ctx context.Context, r *http.Request) (map[string]bool, error)
+}
+
+type featureFlagsContextKey struct{}
+
+// NewFeatureFlagMiddleware creates a middleware that stores the feature flags of every request, returned by the
+// provider, in the request context, readable with FeatureFlagsFromContext. Errors of the provider are logged with
+// the default slog logger without failing the request, storing the flags returned with the error, if any.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewFeatureFlagMiddleware(flagProvider)).Get("/checkout", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /checkout'
+//		with the feature flags of the request in the request context
+func NewFeatureFlagMiddleware(provider FeatureFlagProvider) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			flags, err := provider.Flags(r.Context(), r)
+			if err != nil {
+				slog.Error("supermuxer: reading feature flags", "error", err)
+			}
+
+			if flags == nil {
+				flags = map[string]bool{}
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), featureFlagsContextKey{}, flags)))
+		}
+	}
+}
+
+// FeatureFlagsFromContext returns the feature flags stored by the middleware created by NewFeatureFlagMiddleware,
+// or nil if there are none. Reading a missing flag of the map returns false.
+func FeatureFlagsFromContext(ctx context.Context) map[string]bool {
+	flags, _ := ctx.Value(featureFlagsContextKey{}).(map[string]bool)
+	return flags
+}
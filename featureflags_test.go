@@ -0,0 +1,76 @@
+package supermuxer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockFeatureFlagProvider struct {
+	flags map[string]bool
+	err   error
+}
+
+func (p mockFeatureFlagProvider) Flags(ctx context.Context, r *http.Request) (map[string]bool, error) {
+	if r.Header.Get("X-Beta") == "true" {
+		return map[string]bool{"new-checkout": true, "dark-mode": true}, p.err
+	}
+
+	return p.flags, p.err
+}
+
+func TestFeatureFlagMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider mockFeatureFlagProvider
+		beta     bool
+		want     map[string]bool
+	}{
+		{name: "provider flags", provider: mockFeatureFlagProvider{flags: map[string]bool{"new-checkout": false, "dark-mode": true}},
+			want: map[string]bool{"new-checkout": false, "dark-mode": true}},
+		{name: "flags by request", provider: mockFeatureFlagProvider{}, beta: true,
+			want: map[string]bool{"new-checkout": true, "dark-mode": true}},
+		{name: "provider error", provider: mockFeatureFlagProvider{err: errors.New("flags service unavailable")},
+			want: map[string]bool{"new-checkout": false, "dark-mode": false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var flags map[string]bool
+			handler := NewFeatureFlagMiddleware(tt.provider)(func(w http.ResponseWriter, r *http.Request) {
+				flags = FeatureFlagsFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+			if tt.beta {
+				req.Header.Set("X-Beta", "true")
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			if flags == nil {
+				t.Fatal("FeatureFlagsFromContext() = nil, want the flags")
+			}
+
+			for name, want := range tt.want {
+				if flags[name] != want {
+					t.Errorf("flag %q = %v, want %v", name, flags[name], want)
+				}
+			}
+		})
+	}
+
+	t.Run("without middleware", func(t *testing.T) {
+		if flags := FeatureFlagsFromContext(context.Background()); flags != nil {
+			t.Fatalf("FeatureFlagsFromContext() = %v, want nil", flags)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package supermuxer
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+)
+
+// FingerprintHasher computes the fingerprint of a request for the middleware created by
+// NewRequestFingerprintMiddleware. Implementations must be safe for concurrent use.
+type FingerprintHasher interface {
+	Hash(r *http.Request) string
+}
+
+// FingerprintConfig configures the middleware created by NewRequestFingerprintMiddlewareWithConfig.
+type FingerprintConfig struct {
+	// Hasher computes the fingerprints. Defaults to DefaultFingerprintHasher.
+	Hasher FingerprintHasher
+	// HeaderName is the response header set to the fingerprint, e.g. 'X-Fingerprint'. Empty does not set it.
+	HeaderName string
+}
+
+// DefaultFingerprintHasher is a FingerprintHasher that hashes the client IP, the 'User-Agent' header and the
+// 'Accept' header of the request with FNV-1a, formatted as 16 hexadecimal digits.
+type DefaultFingerprintHasher struct{}
+
+type fingerprintContextKey struct{}
+
+func (DefaultFingerprintHasher) Hash(r *http.Request) string {
+	hash := fnv.New64a()
+
+	for _, value := range []string{clientIP(r), r.UserAgent(), r.Header.Get("Accept")} {
+		hash.Write([]byte(value))
+		// The separator keeps 'ab' + 'c' and 'a' + 'bc' apart.
+		hash.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+// NewRequestFingerprintMiddleware creates a middleware that computes the fingerprint of every request with the
+// hasher, stored in the request context, readable with FingerprintFromContext. A nil hasher uses
+// DefaultFingerprintHasher.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewRequestFingerprintMiddleware(supermuxer.DefaultFingerprintHasher{})).Post("/login", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /login'
+//		with the fingerprint of the client IP, 'User-Agent' and 'Accept' headers
+func NewRequestFingerprintMiddleware(hasher FingerprintHasher) MiddlewareFunc {
+	return NewRequestFingerprintMiddlewareWithConfig(FingerprintConfig{Hasher: hasher})
+}
+
+// NewRequestFingerprintMiddlewareWithConfig works the same way as NewRequestFingerprintMiddleware, and also sets
+// the fingerprint in the HeaderName response header.
+func NewRequestFingerprintMiddlewareWithConfig(cfg FingerprintConfig) MiddlewareFunc {
+	if cfg.Hasher == nil {
+		cfg.Hasher = DefaultFingerprintHasher{}
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			fingerprint := cfg.Hasher.Hash(r)
+
+			if cfg.HeaderName != "" {
+				w.Header().Set(cfg.HeaderName, fingerprint)
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), fingerprintContextKey{}, fingerprint)))
+		}
+	}
+}
+
+// FingerprintFromContext returns the fingerprint stored by the middleware created by NewRequestFingerprintMiddleware,
+// or an empty string if there is none.
+func FingerprintFromContext(ctx context.Context) string {
+	fingerprint, _ := ctx.Value(fingerprintContextKey{}).(string)
+	return fingerprint
+}
@@ -0,0 +1,75 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+type staticFingerprintHasher string
+
+func (h staticFingerprintHasher) Hash(*http.Request) string { return string(h) }
+
+func TestRequestFingerprintMiddleware(t *testing.T) {
+	newRequest := func(remoteAddr, userAgent, accept string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("Accept", accept)
+		return req
+	}
+
+	fingerprint := func(middleware MiddlewareFunc, req *http.Request) (string, *httptest.ResponseRecorder) {
+		var got string
+		rec := httptest.NewRecorder()
+		middleware(func(w http.ResponseWriter, r *http.Request) {
+			got = FingerprintFromContext(r.Context())
+		})(rec, req)
+
+		return got, rec
+	}
+
+	middleware := NewRequestFingerprintMiddleware(nil)
+	base, _ := fingerprint(middleware, newRequest("203.0.113.9:4312", "Firefox/130.0", "text/html"))
+
+	if !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(base) {
+		t.Fatalf("fingerprint = %q, want 16 hexadecimal digits", base)
+	}
+
+	t.Run("identical requests", func(t *testing.T) {
+		// The port of the client changes between connections.
+		if got, _ := fingerprint(middleware, newRequest("203.0.113.9:5001", "Firefox/130.0", "text/html")); got != base {
+			t.Fatalf("fingerprint = %q, want %q", got, base)
+		}
+	})
+
+	different := []struct {
+		name string
+		req  *http.Request
+	}{
+		{name: "IP", req: newRequest("203.0.113.10:4312", "Firefox/130.0", "text/html")},
+		{name: "User-Agent", req: newRequest("203.0.113.9:4312", "Chrome/129.0", "text/html")},
+		{name: "Accept", req: newRequest("203.0.113.9:4312", "Firefox/130.0", "application/json")},
+		{name: "moved characters", req: newRequest("203.0.113.9:4312", "Firefox/130.0text/", "html")},
+	}
+
+	for _, tt := range different {
+		t.Run("different "+tt.name, func(t *testing.T) {
+			if got, _ := fingerprint(middleware, tt.req); got == base {
+				t.Fatalf("fingerprint = %q, want it to differ from the base request", got)
+			}
+		})
+	}
+
+	t.Run("custom hasher and header", func(t *testing.T) {
+		got, rec := fingerprint(NewRequestFingerprintMiddlewareWithConfig(FingerprintConfig{
+			Hasher:     staticFingerprintHasher("device-1"),
+			HeaderName: "X-Fingerprint",
+		}), newRequest("203.0.113.9:4312", "Firefox/130.0", "text/html"))
+
+		if got != "device-1" || rec.Header().Get("X-Fingerprint") != "device-1" {
+			t.Fatalf("fingerprint = %q, header = %q, want %q", got, rec.Header().Get("X-Fingerprint"), "device-1")
+		}
+	})
+}
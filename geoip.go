@@ -0,0 +1,71 @@
+package supermuxer
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+)
+
+// GeoIPResult is the location of a client IP found in a GeoIPDatabase.
+type GeoIPResult struct {
+	CountryCode string
+	Region      string
+	City        string
+}
+
+// GeoIPDatabase finds the location of the client IPs for the middleware created by NewGeoIPMiddleware,
+// e.g. backed by a MaxMind database. Implementations must be safe for concurrent use.
+type GeoIPDatabase interface {
+	Lookup(ip net.IP) (GeoIPResult, error)
+}
+
+type geoIPContextKey struct{}
+
+// NewGeoIPMiddleware creates a middleware that looks up the location of the client IP of every request in the
+// database, and stores it in the request context, readable with GeoIPFromContext. The client IP is the first IP of
+// the 'X-Forwarded-For' header, or the 'X-Real-IP' header, falling back to the request peer, so the headers must only
+// be trusted behind a proxy. Lookup errors are logged with the default slog logger and the request is handled
+// without a location.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewGeoIPMiddleware(db)).Post("/payments", func(w http.ResponseWriter, r *http.Request) {
+//		location, ok := supermuxer.GeoIPFromContext(r.Context())
+//	})
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /payments'
+//		with the location of the client
+func NewGeoIPMiddleware(db GeoIPDatabase) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			address := forwardedClientIP(r)
+
+			ip := net.ParseIP(address)
+			if ip == nil {
+				slog.Warn("supermuxer: parsing client IP", "ip", address)
+				next(w, r)
+				return
+			}
+
+			result, err := db.Lookup(ip)
+			if err != nil {
+				slog.Warn("supermuxer: looking up client IP location", "ip", address, "error", err)
+				next(w, r)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), geoIPContextKey{}, result)))
+		}
+	}
+}
+
+// GeoIPFromContext returns the location stored by the middleware created by NewGeoIPMiddleware.
+func GeoIPFromContext(ctx context.Context) (GeoIPResult, bool) {
+	result, ok := ctx.Value(geoIPContextKey{}).(GeoIPResult)
+	return result, ok
+}
@@ -0,0 +1,74 @@
+package supermuxer
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockGeoIPDatabase map[string]GeoIPResult
+
+func (db mockGeoIPDatabase) Lookup(ip net.IP) (GeoIPResult, error) {
+	result, ok := db[ip.String()]
+	if !ok {
+		return GeoIPResult{}, errors.New("address not found")
+	}
+
+	return result, nil
+}
+
+func TestGeoIPMiddleware(t *testing.T) {
+	db := mockGeoIPDatabase{
+		"203.0.113.9":  {CountryCode: "PT", Region: "Lisbon", City: "Lisbon"},
+		"198.51.100.4": {CountryCode: "US", Region: "California", City: "San Francisco"},
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		realIP       string
+		want         GeoIPResult
+		wantLocation bool
+	}{
+		{name: "peer address", remoteAddr: "203.0.113.9:4312", want: db["203.0.113.9"], wantLocation: true},
+		{name: "forwarded for", remoteAddr: "10.0.0.1:4312", forwardedFor: "198.51.100.4, 10.0.0.2", want: db["198.51.100.4"], wantLocation: true},
+		{name: "real IP", remoteAddr: "10.0.0.1:4312", realIP: "203.0.113.9", want: db["203.0.113.9"], wantLocation: true},
+		{name: "lookup error", remoteAddr: "192.0.2.1:4312"},
+		{name: "invalid IP", remoteAddr: "10.0.0.1:4312", forwardedFor: "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got GeoIPResult
+			var ok bool
+			handler := NewGeoIPMiddleware(db)(func(w http.ResponseWriter, r *http.Request) {
+				got, ok = GeoIPFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			// Lookup errors do not abort the request.
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			if ok != tt.wantLocation || got != tt.want {
+				t.Fatalf("GeoIPFromContext() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantLocation)
+			}
+		})
+	}
+}
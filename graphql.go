@@ -0,0 +1,74 @@
+package supermuxer
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+)
+
+// GraphQLParams is the operation of a GraphQL request, decoded by the middleware created by NewGraphQLMiddleware.
+type GraphQLParams struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables"`
+	OperationName string         `json:"operationName"`
+}
+
+// GraphQLError is an error of a GraphQLResult.
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLResult is the response of a GraphQL operation, encoded by the middleware created by NewGraphQLMiddleware.
+type GraphQLResult struct {
+	Data   any            `json:"data,omitempty"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// GraphQLSchema executes the GraphQL operations for the middleware created by NewGraphQLMiddleware,
+// e.g. backed by a GraphQL library. Implementations must be safe for concurrent use.
+type GraphQLSchema interface {
+	Execute(ctx context.Context, params GraphQLParams) *GraphQLResult
+}
+
+// NewGraphQLMiddleware creates a middleware that serves the GraphQL operations with the schema, instead of calling
+// the next handler, for the 'POST' requests with a JSON body like '{"query": "...", "variables": {...}}'.
+// The result is encoded as JSON with 200, and bodies without a query get 400 with the error in the result.
+// The other requests, e.g. for GraphQL subscriptions over WebSocket, go to the next handler.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewGraphQLMiddleware(schema)).Any("/graphql", subscriptionsHandler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /graphql' with the schema,
+//		and the other requests for '/graphql' with subscriptionsHandler
+func NewGraphQLMiddleware(schema GraphQLSchema) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if r.Method != http.MethodPost || mediaType != "application/json" {
+				next(w, r)
+				return
+			}
+
+			var params GraphQLParams
+			if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params.Query == "" {
+				writeJSON(w, http.StatusBadRequest, GraphQLResult{Errors: []GraphQLError{{Message: "invalid GraphQL request"}}})
+				return
+			}
+
+			result := schema.Execute(r.Context(), params)
+			if result == nil {
+				result = &GraphQLResult{}
+			}
+
+			writeJSON(w, http.StatusOK, result)
+		}
+	}
+}
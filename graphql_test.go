@@ -0,0 +1,142 @@
+package supermuxer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type mockGraphQLSchema struct {
+	params  []GraphQLParams
+	userIDs []any
+	result  *GraphQLResult
+}
+
+func (s *mockGraphQLSchema) Execute(ctx context.Context, params GraphQLParams) *GraphQLResult {
+	s.params = append(s.params, params)
+	s.userIDs = append(s.userIDs, ctx.Value(graphQLUserKey{}))
+	return s.result
+}
+
+type graphQLUserKey struct{}
+
+func TestGraphQLMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		result      *GraphQLResult
+		wantStatus  int
+		wantBody    string
+		wantParams  []GraphQLParams
+	}{
+		{
+			name:        "query",
+			method:      http.MethodPost,
+			contentType: "application/json; charset=utf-8",
+			body:        `{"query": "query User($id: ID!) { user(id: $id) { name } }", "variables": {"id": "42"}, "operationName": "User"}`,
+			result:      &GraphQLResult{Data: map[string]any{"user": map[string]any{"name": "Ada"}}},
+			wantStatus:  http.StatusOK,
+			wantBody:    `{"data":{"user":{"name":"Ada"}}}`,
+			wantParams: []GraphQLParams{{
+				Query:         "query User($id: ID!) { user(id: $id) { name } }",
+				Variables:     map[string]any{"id": "42"},
+				OperationName: "User",
+			}},
+		},
+		{
+			name:        "execution errors",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        `{"query": "{ user(id: 1) { name } }"}`,
+			result: &GraphQLResult{Errors: []GraphQLError{{
+				Message:    "user not found",
+				Path:       []any{"user"},
+				Extensions: map[string]any{"code": "NOT_FOUND"},
+			}}},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"errors":[{"message":"user not found","path":["user"],"extensions":{"code":"NOT_FOUND"}}]}`,
+			wantParams: []GraphQLParams{{Query: "{ user(id: 1) { name } }"}},
+		},
+		{
+			name:        "nil result",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        `{"query": "{ ping }"}`,
+			wantStatus:  http.StatusOK,
+			wantBody:    `{}`,
+			wantParams:  []GraphQLParams{{Query: "{ ping }"}},
+		},
+		{
+			name:        "missing query",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        `{"variables": {"id": "42"}}`,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"errors":[{"message":"invalid GraphQL request"}]}`,
+		},
+		{
+			name:        "invalid JSON",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        `{"query": `,
+			wantStatus:  http.StatusBadRequest,
+			wantBody:    `{"errors":[{"message":"invalid GraphQL request"}]}`,
+		},
+		{
+			name:        "other content type",
+			method:      http.MethodPost,
+			contentType: "application/graphql",
+			body:        `{ ping }`,
+			wantStatus:  http.StatusOK,
+			wantBody:    "next",
+		},
+		{
+			name:       "other method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantBody:   "next",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &mockGraphQLSchema{result: tt.result}
+			handler := NewGraphQLMiddleware(schema)(textHandler("next"))
+
+			req := httptest.NewRequest(tt.method, "/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			req = req.WithContext(context.WithValue(req.Context(), graphQLUserKey{}, "user-1"))
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if got := strings.TrimSpace(rec.Body.String()); got != tt.wantBody {
+				t.Fatalf("body = %s, want %s", got, tt.wantBody)
+			}
+
+			if tt.wantBody != "next" && !json.Valid(rec.Body.Bytes()) {
+				t.Fatalf("body = %s, want valid JSON", rec.Body.String())
+			}
+
+			if !reflect.DeepEqual(schema.params, tt.wantParams) {
+				t.Fatalf("params = %+v, want %+v", schema.params, tt.wantParams)
+			}
+
+			for _, userID := range schema.userIDs {
+				if userID != "user-1" {
+					t.Fatalf("user in the schema context = %v, want %q", userID, "user-1")
+				}
+			}
+		})
+	}
+}
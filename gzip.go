@@ -0,0 +1,215 @@
+package supermuxer
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultGzipMinSize is the minimum size, in bytes, of the responses compressed by NewGzipMiddleware.
+const DefaultGzipMinSize = 1024
+
+// NewGzipMiddleware creates a middleware that compresses with gzip, at the compress/gzip level, the responses
+// of at least DefaultGzipMinSize bytes for the clients that accept it. It panics if the level is invalid.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewGzipMiddleware(gzip.DefaultCompression))
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users' with a gzip response
+func NewGzipMiddleware(level int) MiddlewareFunc {
+	return NewGzipMiddlewareWithMinSize(level, DefaultGzipMinSize)
+}
+
+// NewGzipMiddlewareWithMinSize works the same way as NewGzipMiddleware, but only compresses the responses
+// of at least minSize bytes.
+func NewGzipMiddlewareWithMinSize(level int, minSize int) MiddlewareFunc {
+	if _, err := gzip.NewWriterLevel(io.Discard, level); err != nil {
+		panic(fmt.Sprintf("supermuxer: invalid gzip level %d", level))
+	}
+
+	newEncoder := func(w io.Writer) io.WriteCloser {
+		encoder, _ := gzip.NewWriterLevel(w, level)
+		return encoder
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			if !acceptsEncoding(r, "gzip") {
+				next(w, r)
+				return
+			}
+
+			cw := newCompressWriter(w, "gzip", minSize, newEncoder)
+			next(cw, r)
+
+			// Not deferred, so a panicking handler does not commit its buffered response before the recovery one.
+			cw.Close()
+		}
+	}
+}
+
+// acceptsEncoding reports whether the 'Accept-Encoding' request header accepts the encoding with a non-zero quality.
+func acceptsEncoding(r *http.Request, encoding string) bool {
+	return encodingQuality(r.Header.Get("Accept-Encoding"), encoding) > 0
+}
+
+// encodingQuality returns the quality of the encoding in the 'Accept-Encoding' header, falling back to '*'.
+func encodingQuality(acceptEncoding string, encoding string) float64 {
+	wildcard := 0.0
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.TrimSpace(name)
+		q := 1.0
+
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		switch {
+		case strings.EqualFold(name, encoding):
+			return q
+		case name == "*":
+			wildcard = q
+		}
+	}
+
+	return wildcard
+}
+
+// compressWriter buffers the response until it reaches the minimum size and then compresses it with the encoder.
+// Smaller responses are written as they are when the writer is closed.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding   string
+	minSize    int
+	newEncoder func(io.Writer) io.WriteCloser
+
+	status      int
+	buf         []byte
+	encoder     io.WriteCloser
+	passthrough bool
+}
+
+func newCompressWriter(w http.ResponseWriter, encoding string, minSize int, newEncoder func(io.Writer) io.WriteCloser) *compressWriter {
+	return &compressWriter{
+		ResponseWriter: w,
+		encoding:       encoding,
+		minSize:        minSize,
+		newEncoder:     newEncoder,
+	}
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+
+	w.status = code
+
+	// Informational and bodiless responses are not compressed.
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified || w.Header().Get("Content-Encoding") != "" {
+		w.passthrough = true
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) >= w.minSize {
+		if err := w.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+func (w *compressWriter) startEncoding() error {
+	header := w.Header()
+	header.Del("Content-Length")
+	header.Set("Content-Encoding", w.encoding)
+
+	// The content type must be sniffed from the uncompressed bytes.
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.encoder = w.newEncoder(w.ResponseWriter)
+
+	_, err := w.encoder.Write(w.buf)
+	w.buf = nil
+
+	return err
+}
+
+// Flush starts compressing the buffered response, whatever its size, and flushes it to the client.
+func (w *compressWriter) Flush() {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if !w.passthrough && w.encoder == nil {
+		w.startEncoding()
+	}
+
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close writes the buffered response uncompressed when it is smaller than the minimum size,
+// or finishes the compressed response.
+func (w *compressWriter) Close() error {
+	if w.encoder != nil {
+		return w.encoder.Close()
+	}
+
+	if w.passthrough {
+		return nil
+	}
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(w.buf)
+
+	return err
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,109 @@
+package supermuxer
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGzipMiddleware(t *testing.T) {
+	large := strings.Repeat("supermuxer ", 200)
+
+	newRequest := func(acceptEncoding string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+
+		return req
+	}
+
+	write := func(w http.ResponseWriter, r *http.Request) {
+		body := r.URL.Query().Get("body")
+		if body == "" {
+			body = large
+		}
+
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write([]byte(body))
+	}
+	handler := NewGzipMiddleware(gzip.BestSpeed)(write)
+
+	t.Run("compressed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler(rec, newRequest("br, gzip;q=0.8"))
+
+		if rec.Header().Get("Content-Encoding") != "gzip" || rec.Header().Get("Content-Length") != "" {
+			t.Fatalf("headers = %v, want 'Content-Encoding: gzip' without Content-Length", rec.Header())
+		}
+
+		if rec.Header().Get("Vary") != "Accept-Encoding" {
+			t.Fatalf("Vary = %q, want %q", rec.Header().Get("Vary"), "Accept-Encoding")
+		}
+
+		reader, err := gzip.NewReader(rec.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := io.ReadAll(reader)
+		if err != nil || string(body) != large {
+			t.Fatalf("decompressed body = %d bytes, %v, want %d bytes", len(body), err, len(large))
+		}
+	})
+
+	t.Run("not accepted", func(t *testing.T) {
+		for _, acceptEncoding := range []string{"", "br", "gzip;q=0"} {
+			rec := httptest.NewRecorder()
+			handler(rec, newRequest(acceptEncoding))
+
+			if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != large {
+				t.Errorf("Accept-Encoding %q: response compressed, want it unchanged", acceptEncoding)
+			}
+		}
+	})
+
+	t.Run("below the minimum size", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := newRequest("gzip")
+		req.URL.RawQuery = "body=small"
+		handler(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.String() != "small" || rec.Header().Get("Content-Length") != "5" {
+			t.Fatalf("response = %v %q, want it unchanged", rec.Header(), rec.Body.String())
+		}
+	})
+
+	t.Run("minimum size", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := newRequest("gzip")
+		req.URL.RawQuery = "body=small"
+		NewGzipMiddlewareWithMinSize(gzip.DefaultCompression, 1)(write)(rec, req)
+
+		if rec.Header().Get("Content-Encoding") != "gzip" {
+			t.Fatalf("Content-Encoding = %q, want gzip", rec.Header().Get("Content-Encoding"))
+		}
+	})
+
+	t.Run("panicking handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		PanicRecovery(nil)(NewGzipMiddleware(gzip.BestSpeed)(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("partial"))
+			panic("boom")
+		}))(rec, newRequest("gzip"))
+
+		if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "partial") {
+			t.Fatalf("response = %d %q, want %d without the buffered body", rec.Code, rec.Body.String(), http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		assertPanics(t, "invalid gzip level 42", func() {
+			NewGzipMiddleware(42)
+		})
+	})
+}
@@ -0,0 +1,58 @@
+package supermuxer
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HealthCheckFunc checks a dependency of the application for HealthCheck, returning an error if it is not healthy.
+// It must return when the context is done.
+type HealthCheckFunc func(ctx context.Context) error
+
+// healthCheckTimeout is the time the checks of HealthCheck have to complete.
+const healthCheckTimeout = 5 * time.Second
+
+func (r *router) HealthCheck(path string, checks ...HealthCheckFunc) *router {
+	return r.Get(path, func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), healthCheckTimeout)
+		defer cancel()
+
+		errs := make([]chan error, len(checks))
+		for i, check := range checks {
+			errs[i] = make(chan error, 1)
+			go func() {
+				errs[i] <- check(ctx)
+			}()
+		}
+
+		results := make(map[string]string, len(checks))
+		failed := 0
+
+		for i := range checks {
+			var err error
+			select {
+			case err = <-errs[i]:
+			case <-ctx.Done():
+				err = ctx.Err()
+			}
+
+			results[strconv.Itoa(i)] = "ok"
+			if err != nil {
+				results[strconv.Itoa(i)] = err.Error()
+				failed++
+			}
+		}
+
+		status, code := "ok", http.StatusOK
+		switch {
+		case failed > 0 && failed == len(checks):
+			status, code = "unhealthy", http.StatusServiceUnavailable
+		case failed > 0:
+			status, code = "degraded", http.StatusMultiStatus
+		}
+
+		writeJSON(w, code, map[string]any{"status": status, "checks": results})
+	})
+}
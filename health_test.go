@@ -0,0 +1,80 @@
+package supermuxer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthCheck(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("database unreachable") }
+
+	tests := []struct {
+		name   string
+		checks []HealthCheckFunc
+		code   int
+		status string
+		want   map[string]string
+	}{
+		{name: "no checks", code: http.StatusOK, status: "ok", want: map[string]string{}},
+		{name: "healthy", checks: []HealthCheckFunc{healthy, healthy}, code: http.StatusOK, status: "ok",
+			want: map[string]string{"0": "ok", "1": "ok"}},
+		{name: "degraded", checks: []HealthCheckFunc{healthy, failing}, code: http.StatusMultiStatus, status: "degraded",
+			want: map[string]string{"0": "ok", "1": "database unreachable"}},
+		{name: "unhealthy", checks: []HealthCheckFunc{failing}, code: http.StatusServiceUnavailable, status: "unhealthy",
+			want: map[string]string{"0": "database unreachable"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			New(mux).HealthCheck("/healthz", tt.checks...)
+
+			rec := serve(mux, http.MethodGet, "/healthz")
+			if rec.Code != tt.code {
+				t.Fatalf("status code = %d, want %d", rec.Code, tt.code)
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Fatalf("Content-Type = %q, want %q", got, "application/json")
+			}
+
+			var body struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("json.Unmarshal(%q) error = %v", rec.Body.String(), err)
+			}
+
+			if body.Status != tt.status || !maps.Equal(body.Checks, tt.want) {
+				t.Fatalf("body = %+v, want status %q with %v", body, tt.status, tt.want)
+			}
+		})
+	}
+
+	t.Run("done context", func(t *testing.T) {
+		blocking := func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		mux := http.NewServeMux()
+		New(mux).HealthCheck("/healthz", blocking)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequestWithContext(ctx, http.MethodGet, "/healthz", nil))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status code = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	})
+}
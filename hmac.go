@@ -0,0 +1,98 @@
+package supermuxer
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HMACConfig configures the middleware created by NewHMACSignatureMiddleware.
+type HMACConfig struct {
+	// Secret is the shared key of the signatures. It is required.
+	Secret []byte
+	// HeaderName is the request header holding the hex signature, optionally prefixed like 'sha256=<hex>'.
+	// Defaults to 'X-Signature'.
+	HeaderName string
+	// HashFunc is the hash of the HMAC. Defaults to sha256.New.
+	HashFunc func() hash.Hash
+	// MaxBodySize is the maximum number of bytes of the signed body. Defaults to 1 MiB.
+	MaxBodySize int64
+}
+
+// NewHMACSignatureMiddleware creates a middleware that verifies the HMAC signature of the request body, like the
+// webhooks of GitHub or Stripe. The body is buffered and replaced, so the next handler can still read it.
+// Requests with a missing or invalid signature get 401, and bodies over MaxBodySize get 413.
+// It panics if the secret is empty.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	signature := supermuxer.NewHMACSignatureMiddleware(supermuxer.HMACConfig{Secret: secret, HeaderName: "X-Hub-Signature-256"})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(signature).Post("/webhooks", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /webhooks'
+//		responding with 401 unless the 'X-Hub-Signature-256' header signs the body
+func NewHMACSignatureMiddleware(cfg HMACConfig) MiddlewareFunc {
+	if len(cfg.Secret) == 0 {
+		panic("supermuxer: HMAC secret is required")
+	}
+
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = "X-Signature"
+	}
+
+	if cfg.HashFunc == nil {
+		cfg.HashFunc = sha256.New
+	}
+
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = 1 << 20
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(cfg.HeaderName)
+			if _, value, ok := strings.Cut(header, "="); ok {
+				header = value
+			}
+
+			signature, err := hex.DecodeString(header)
+			if header == "" || err != nil {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, cfg.MaxBodySize))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+					return
+				}
+
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			mac := hmac.New(cfg.HashFunc, cfg.Secret)
+			mac.Write(body)
+
+			if !hmac.Equal(signature, mac.Sum(nil)) {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next(w, r)
+		}
+	}
+}
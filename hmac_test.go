@@ -0,0 +1,89 @@
+package supermuxer
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestHMACSignatureMiddleware(t *testing.T) {
+	secret := []byte("webhook-secret")
+	payload := `{"event":"push"}`
+
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	handler := NewHMACSignatureMiddleware(HMACConfig{Secret: secret, MaxBodySize: 64})(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})
+
+	tests := []struct {
+		name      string
+		body      io.Reader
+		signature string
+		status    int
+	}{
+		{name: "correct signature", body: strings.NewReader(payload), signature: sign(payload), status: http.StatusOK},
+		{name: "prefixed signature", body: strings.NewReader(payload), signature: "sha256=" + sign(payload), status: http.StatusOK},
+		{name: "tampered body", body: strings.NewReader(`{"event":"delete"}`), signature: sign(payload), status: http.StatusUnauthorized},
+		{name: "missing header", body: strings.NewReader(payload), status: http.StatusUnauthorized},
+		{name: "malformed header", body: strings.NewReader(payload), signature: "not-hex", status: http.StatusUnauthorized},
+		{name: "truncated body", body: strings.NewReader(payload[:len(payload)/2]), signature: sign(payload), status: http.StatusUnauthorized},
+		{name: "interrupted body", body: io.MultiReader(strings.NewReader(payload[:4]), iotest.ErrReader(errors.New("connection reset"))), signature: sign(payload), status: http.StatusBadRequest},
+		{name: "body above the limit", body: strings.NewReader(strings.Repeat("a", 65)), signature: sign(strings.Repeat("a", 65)), status: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", tt.body)
+			if tt.signature != "" {
+				req.Header.Set("X-Signature", tt.signature)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if tt.status == http.StatusOK && rec.Body.String() != payload {
+				t.Fatalf("body read by the handler = %q, want %q", rec.Body.String(), payload)
+			}
+		})
+	}
+
+	t.Run("custom header and hash", func(t *testing.T) {
+		handler := NewHMACSignatureMiddleware(HMACConfig{Secret: secret, HeaderName: "X-Hub-Signature", HashFunc: sha512.New})(textHandler("ok"))
+
+		mac := hmac.New(sha512.New, secret)
+		mac.Write([]byte(payload))
+
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(payload))
+		req.Header.Set("X-Hub-Signature", "sha512="+hex.EncodeToString(mac.Sum(nil)))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		assertPanics(t, "HMAC secret is required", func() {
+			NewHMACSignatureMiddleware(HMACConfig{})
+		})
+	})
+}
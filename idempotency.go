@@ -0,0 +1,196 @@
+package supermuxer
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// IdempotentResponse is a response stored by the middleware created by NewIdempotencyMiddleware.
+type IdempotentResponse struct {
+	Status int
+	// Header holds the headers set by the next handler, except 'Set-Cookie'.
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore stores the responses of the middleware created by NewIdempotencyMiddleware
+// by their idempotency keys. Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Get returns the response stored for the key, and whether there is one.
+	Get(key string) (IdempotentResponse, bool)
+	// Set stores the response for the key.
+	Set(key string, resp IdempotentResponse)
+}
+
+// DefaultIdempotencyTTL is how long the store created by NewMemoryIdempotencyStore keeps the responses.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// idempotencySweepInterval is the minimum time between two sweeps of the expired responses of a memory store.
+const idempotencySweepInterval = time.Minute
+
+type memoryIdempotencyEntry struct {
+	resp      IdempotentResponse
+	expiresAt time.Time
+}
+
+// memoryIdempotencyStore is an IdempotencyStore that keeps the responses in memory for the ttl.
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+// NewMemoryIdempotencyStore creates an IdempotencyStore that keeps the responses in memory for
+// DefaultIdempotencyTTL.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return NewMemoryIdempotencyStoreWithTTL(DefaultIdempotencyTTL)
+}
+
+// NewMemoryIdempotencyStoreWithTTL works the same way as NewMemoryIdempotencyStore, keeping the responses for the ttl.
+// The expired responses are removed from memory when new ones are stored. It panics if the ttl is not positive.
+func NewMemoryIdempotencyStoreWithTTL(ttl time.Duration) IdempotencyStore {
+	if ttl <= 0 {
+		panic("supermuxer: idempotency store TTL must be positive")
+	}
+
+	return &memoryIdempotencyStore{ttl: ttl, entries: map[string]memoryIdempotencyEntry{}, lastSweep: time.Now()}
+}
+
+func (s *memoryIdempotencyStore) Get(key string) (IdempotentResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return IdempotentResponse{}, false
+	}
+
+	return entry.resp, true
+}
+
+func (s *memoryIdempotencyStore) Set(key string, resp IdempotentResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= idempotencySweepInterval {
+		for entryKey, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, entryKey)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	resp.Header = resp.Header.Clone()
+	resp.Body = slices.Clone(resp.Body)
+	s.entries[key] = memoryIdempotencyEntry{resp: resp, expiresAt: now.Add(s.ttl)}
+}
+
+// NewIdempotencyMiddleware creates a middleware that deduplicates the requests of the same caller with the same
+// 'Idempotency-Key' header for the same method and path. The callers are identified by the 'sub' claim stored by the
+// middleware created by NewJWTMiddleware under the default key, falling back to the client IP, so a caller can never
+// get the response of another one. The first response of a key is stored, with the headers set by the next handler
+// except 'Set-Cookie', and the next requests with the key get it replayed with the 'Idempotent-Replayed: true'
+// header, without calling the next handler. Requests with a key whose first request is still running get 409.
+// Responses with 5xx status codes are not stored, so the request can be retried. Requests without the header are not
+// changed. The running requests are tracked by the middleware, so they are only detected within the same process.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	idempotency := supermuxer.NewIdempotencyMiddleware(supermuxer.NewMemoryIdempotencyStore())
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(idempotency).Post("/payments", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /payments'
+//		only once for each caller and 'Idempotency-Key' header
+func NewIdempotencyMiddleware(store IdempotencyStore) MiddlewareFunc {
+	return NewIdempotencyMiddlewareWithKeyFunc(store, jwtSubjectOrClientIP)
+}
+
+// NewIdempotencyMiddlewareWithKeyFunc works the same way as NewIdempotencyMiddleware, identifying the callers with
+// keyFunc, e.g. by their API key or tenant.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	idempotency := supermuxer.NewIdempotencyMiddlewareWithKeyFunc(supermuxer.NewMemoryIdempotencyStore(), func(r *http.Request) string {
+//		return r.Header.Get("X-API-Key")
+//	})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(idempotency).Post("/payments", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /payments'
+//		only once for each API key and 'Idempotency-Key' header
+func NewIdempotencyMiddlewareWithKeyFunc(store IdempotencyStore, keyFunc func(*http.Request) string) MiddlewareFunc {
+	var running sync.Map
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get("Idempotency-Key")
+			if idempotencyKey == "" {
+				next(w, r)
+				return
+			}
+
+			key := keyFunc(r) + "\x00" + r.Method + " " + r.URL.Path + " " + idempotencyKey
+
+			if resp, ok := store.Get(key); ok {
+				replayIdempotentResponse(w, resp)
+				return
+			}
+
+			if _, loaded := running.LoadOrStore(key, struct{}{}); loaded {
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+				return
+			}
+			defer running.Delete(key)
+
+			// The store is read again, as the first request may have finished before the key was reserved.
+			if resp, ok := store.Get(key); ok {
+				replayIdempotentResponse(w, resp)
+				return
+			}
+
+			before := w.Header().Clone()
+			bw := newBufferWriter(w)
+			next(bw, r)
+
+			// Only the headers set by the next handler are stored, as the outer middlewares set theirs again.
+			header := http.Header{}
+			for name, values := range w.Header() {
+				if name != "Set-Cookie" && !slices.Equal(before[name], values) {
+					header[name] = slices.Clone(values)
+				}
+			}
+
+			if bw.Status() < http.StatusInternalServerError {
+				store.Set(key, IdempotentResponse{Status: bw.Status(), Header: header, Body: bw.body.Bytes()})
+			}
+
+			bw.flush()
+		}
+	}
+}
+
+// replayIdempotentResponse writes the stored response, without the cookies, which belong to the first request.
+func replayIdempotentResponse(w http.ResponseWriter, resp IdempotentResponse) {
+	header := w.Header()
+	for name, values := range resp.Header {
+		if http.CanonicalHeaderKey(name) != "Set-Cookie" {
+			header[name] = slices.Clone(values)
+		}
+	}
+	header.Set("Idempotent-Replayed", "true")
+
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
@@ -0,0 +1,280 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockingIdempotencyStore blocks the first Get after armed is set, until release is closed.
+type blockingIdempotencyStore struct {
+	IdempotencyStore
+	armed   atomic.Bool
+	blocked chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingIdempotencyStore) Get(key string) (IdempotentResponse, bool) {
+	if s.armed.CompareAndSwap(true, false) {
+		close(s.blocked)
+		<-s.release
+	}
+
+	return s.IdempotencyStore.Get(key)
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	newHandler := func(calls *atomic.Int32) http.HandlerFunc {
+		return NewIdempotencyMiddleware(NewMemoryIdempotencyStore())(func(w http.ResponseWriter, r *http.Request) {
+			n := calls.Add(1)
+			w.Header().Set("X-Payment-ID", strconv.Itoa(int(n)))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("payment " + strconv.Itoa(int(n))))
+		})
+	}
+
+	send := func(handler http.HandlerFunc, method, target, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("replays the original response", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := newHandler(&calls)
+
+		first := send(handler, http.MethodPost, "/payments", "abc")
+		second := send(handler, http.MethodPost, "/payments", "abc")
+
+		if calls.Load() != 1 {
+			t.Fatalf("handler calls = %d, want 1", calls.Load())
+		}
+
+		if second.Code != first.Code || second.Body.String() != first.Body.String() {
+			t.Fatalf("replay = %d %q, want %d %q", second.Code, second.Body.String(), first.Code, first.Body.String())
+		}
+
+		if got := second.Header().Get("X-Payment-ID"); got != first.Header().Get("X-Payment-ID") {
+			t.Fatalf("replayed X-Payment-ID = %q, want %q", got, first.Header().Get("X-Payment-ID"))
+		}
+
+		if first.Header().Get("Idempotent-Replayed") != "" || second.Header().Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("Idempotent-Replayed = %q then %q, want \"\" then \"true\"",
+				first.Header().Get("Idempotent-Replayed"), second.Header().Get("Idempotent-Replayed"))
+		}
+	})
+
+	t.Run("keys are scoped by method and path", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := newHandler(&calls)
+
+		send(handler, http.MethodPost, "/payments", "abc")
+		send(handler, http.MethodPut, "/payments", "abc")
+		send(handler, http.MethodPost, "/refunds", "abc")
+		send(handler, http.MethodPost, "/payments", "def")
+
+		if calls.Load() != 4 {
+			t.Fatalf("handler calls = %d, want 4", calls.Load())
+		}
+	})
+
+	t.Run("keys are scoped by caller", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := newHandler(&calls)
+
+		for _, remoteAddr := range []string{"192.0.2.1:1234", "192.0.2.2:1234", "192.0.2.1:5678"} {
+			req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+			req.RemoteAddr = remoteAddr
+			req.Header.Set("Idempotency-Key", "abc")
+			handler(httptest.NewRecorder(), req)
+		}
+
+		if calls.Load() != 2 {
+			t.Fatalf("handler calls = %d, want 2", calls.Load())
+		}
+	})
+
+	t.Run("custom key func", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := NewIdempotencyMiddlewareWithKeyFunc(NewMemoryIdempotencyStore(), func(r *http.Request) string {
+			return r.Header.Get("X-API-Key")
+		})(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+		})
+
+		for _, apiKey := range []string{"key-1", "key-2", "key-1"} {
+			req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+			req.Header.Set("X-API-Key", apiKey)
+			req.Header.Set("Idempotency-Key", "abc")
+			handler(httptest.NewRecorder(), req)
+		}
+
+		if calls.Load() != 2 {
+			t.Fatalf("handler calls = %d, want 2", calls.Load())
+		}
+	})
+
+	t.Run("cookies are not replayed", func(t *testing.T) {
+		handler := NewIdempotencyMiddleware(NewMemoryIdempotencyStore())(func(w http.ResponseWriter, r *http.Request) {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "first"})
+			w.Header().Set("X-Payment-ID", "1")
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		first := send(handler, http.MethodPost, "/payments", "abc")
+		second := send(handler, http.MethodPost, "/payments", "abc")
+
+		if first.Header().Get("Set-Cookie") == "" {
+			t.Fatal("first response has no Set-Cookie, want the cookie of the handler")
+		}
+
+		if got := second.Header().Get("Set-Cookie"); got != "" || second.Header().Get("X-Payment-ID") != "1" {
+			t.Fatalf("replayed headers = %v, want X-Payment-ID without Set-Cookie", second.Header())
+		}
+	})
+
+	t.Run("concurrent retries of a stored key are replayed", func(t *testing.T) {
+		var calls atomic.Int32
+		store := &blockingIdempotencyStore{
+			IdempotencyStore: NewMemoryIdempotencyStore(),
+			blocked:          make(chan struct{}),
+			release:          make(chan struct{}),
+		}
+		handler := NewIdempotencyMiddleware(store)(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		send(handler, http.MethodPost, "/payments", "abc")
+		store.armed.Store(true)
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- send(handler, http.MethodPost, "/payments", "abc") }()
+		<-store.blocked
+
+		if rec := send(handler, http.MethodPost, "/payments", "abc"); rec.Code != http.StatusCreated || rec.Header().Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("concurrent retry = %d replayed %q, want the stored %d", rec.Code, rec.Header().Get("Idempotent-Replayed"), http.StatusCreated)
+		}
+
+		close(store.release)
+		if rec := <-done; rec.Code != http.StatusCreated {
+			t.Fatalf("blocked retry status = %d, want %d", rec.Code, http.StatusCreated)
+		}
+
+		if calls.Load() != 1 {
+			t.Fatalf("handler calls = %d, want 1", calls.Load())
+		}
+	})
+
+	t.Run("requests without key are not deduplicated", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := newHandler(&calls)
+
+		send(handler, http.MethodPost, "/payments", "")
+		send(handler, http.MethodPost, "/payments", "")
+
+		if calls.Load() != 2 {
+			t.Fatalf("handler calls = %d, want 2", calls.Load())
+		}
+	})
+
+	t.Run("server errors are not stored", func(t *testing.T) {
+		var calls atomic.Int32
+		handler := NewIdempotencyMiddleware(NewMemoryIdempotencyStore())(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		send(handler, http.MethodPost, "/payments", "abc")
+		if rec := send(handler, http.MethodPost, "/payments", "abc"); rec.Code != http.StatusCreated {
+			t.Fatalf("retry status = %d, want %d", rec.Code, http.StatusCreated)
+		}
+
+		if calls.Load() != 2 {
+			t.Fatalf("handler calls = %d, want 2", calls.Load())
+		}
+	})
+
+	t.Run("running key gets conflict", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		handler := NewIdempotencyMiddleware(NewMemoryIdempotencyStore())(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- send(handler, http.MethodPost, "/payments", "abc") }()
+		<-started
+
+		if rec := send(handler, http.MethodPost, "/payments", "abc"); rec.Code != http.StatusConflict {
+			t.Fatalf("concurrent status = %d, want %d", rec.Code, http.StatusConflict)
+		}
+
+		close(release)
+		if rec := <-done; rec.Code != http.StatusCreated {
+			t.Fatalf("first status = %d, want %d", rec.Code, http.StatusCreated)
+		}
+	})
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	t.Run("expired responses are not returned", func(t *testing.T) {
+		store := NewMemoryIdempotencyStoreWithTTL(10 * time.Millisecond)
+		store.Set("key", IdempotentResponse{Status: http.StatusOK, Body: []byte("ok")})
+
+		if resp, ok := store.Get("key"); !ok || string(resp.Body) != "ok" {
+			t.Fatalf("Get = %q, %v, want \"ok\", true", resp.Body, ok)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		if _, ok := store.Get("key"); ok {
+			t.Fatal("Get returned an expired response")
+		}
+	})
+
+	t.Run("stored responses are copied", func(t *testing.T) {
+		store := NewMemoryIdempotencyStore()
+		body := []byte("ok")
+		store.Set("key", IdempotentResponse{Status: http.StatusOK, Header: http.Header{"X-A": {"1"}}, Body: body})
+		body[0] = 'K'
+
+		if resp, _ := store.Get("key"); string(resp.Body) != "ok" {
+			t.Fatalf("stored body = %q, want %q", resp.Body, "ok")
+		}
+	})
+
+	t.Run("set sweeps expired responses", func(t *testing.T) {
+		store := NewMemoryIdempotencyStoreWithTTL(time.Millisecond).(*memoryIdempotencyStore)
+		store.Set("old", IdempotentResponse{Status: http.StatusOK})
+		time.Sleep(2 * time.Millisecond)
+
+		store.lastSweep = time.Now().Add(-idempotencySweepInterval)
+		store.Set("new", IdempotentResponse{Status: http.StatusOK})
+
+		if _, ok := store.entries["old"]; ok {
+			t.Fatal("expired response was not swept")
+		}
+
+		if _, ok := store.entries["new"]; !ok {
+			t.Fatal("new response was not stored")
+		}
+	})
+
+	t.Run("non-positive ttl", func(t *testing.T) {
+		assertPanics(t, "idempotency store TTL must be positive", func() {
+			NewMemoryIdempotencyStoreWithTTL(0)
+		})
+	})
+}
@@ -0,0 +1,34 @@
+package supermuxer
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// clientIP returns the IP of the request peer, without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// forwardedClientIP returns the first IP of the 'X-Forwarded-For' header, or the 'X-Real-IP' header,
+// falling back to the request peer. The headers can be forged, so they must only be trusted behind a proxy.
+func forwardedClientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		if ip := strings.TrimSpace(first); ip != "" {
+			return ip
+		}
+	}
+
+	if ip := strings.TrimSpace(r.Header.Get("X-Real-IP")); ip != "" {
+		return ip
+	}
+
+	return clientIP(r)
+}
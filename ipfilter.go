@@ -0,0 +1,106 @@
+package supermuxer
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// IPFilterConfig configures the middleware created by NewIPFilterMiddlewareWithConfig.
+type IPFilterConfig struct {
+	// Allow lists the IPs or CIDR ranges allowed. When empty, every IP that is not denied is allowed.
+	Allow []string
+	// Deny lists the IPs or CIDR ranges denied, checked before Allow.
+	Deny []string
+	// OnDeny handles the denied requests. Defaults to 403.
+	OnDeny http.HandlerFunc
+	// TrustForwardedHeaders reads the client IP from the 'X-Forwarded-For' or 'X-Real-IP' headers.
+	// Only enable it behind a proxy that sets them, as clients can forge them.
+	TrustForwardedHeaders bool
+}
+
+// NewIPFilterMiddleware creates a middleware that only lets through the requests from the allowed IPs, checking
+// the denied IPs first. Entries are exact IPs, like '10.0.0.1', or CIDR ranges, like '10.0.0.0/8'.
+// Denied requests are handled by onDeny, which defaults to 403. It panics if an entry is invalid.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	internalOnly := supermuxer.NewIPFilterMiddleware([]string{"10.0.0.0/8"}, []string{"10.0.0.13"}, nil)
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(internalOnly).Get("/metrics", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /metrics'
+//		only from the 10.0.0.0/8 range, except 10.0.0.13
+func NewIPFilterMiddleware(allow []string, deny []string, onDeny http.HandlerFunc) MiddlewareFunc {
+	return NewIPFilterMiddlewareWithConfig(IPFilterConfig{Allow: allow, Deny: deny, OnDeny: onDeny})
+}
+
+// NewIPFilterMiddlewareWithConfig works the same way as NewIPFilterMiddleware, configured with the config.
+func NewIPFilterMiddlewareWithConfig(cfg IPFilterConfig) MiddlewareFunc {
+	allow := parseIPPrefixes(cfg.Allow)
+	deny := parseIPPrefixes(cfg.Deny)
+
+	onDeny := cfg.OnDeny
+	if onDeny == nil {
+		onDeny = func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+	}
+
+	getIP := clientIP
+	if cfg.TrustForwardedHeaders {
+		getIP = forwardedClientIP
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ip, err := netip.ParseAddr(getIP(r))
+			if err != nil || containsIP(deny, ip) || (len(allow) > 0 && !containsIP(allow, ip)) {
+				onDeny(w, r)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// parseIPPrefixes parses exact IPs and CIDR ranges into prefixes, panicking on invalid entries.
+func parseIPPrefixes(entries []string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				panic(fmt.Sprintf("supermuxer: invalid CIDR range %q: %v", entry, err))
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		ip, err := netip.ParseAddr(entry)
+		if err != nil {
+			panic(fmt.Sprintf("supermuxer: invalid IP %q: %v", entry, err))
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(ip.Unmap(), ip.Unmap().BitLen()))
+	}
+
+	return prefixes
+}
+
+func containsIP(prefixes []netip.Prefix, ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIPFilterMiddleware(t *testing.T) {
+	request := func(handler http.HandlerFunc, remoteAddr string, headers map[string]string) int {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.RemoteAddr = remoteAddr
+
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		return rec.Code
+	}
+
+	handler := NewIPFilterMiddleware([]string{"10.0.0.0/8", "192.0.2.7", "2001:db8::/32"}, []string{"10.0.0.13"}, nil)(textHandler("metrics"))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		status     int
+	}{
+		{name: "exact match", remoteAddr: "192.0.2.7:1234", status: http.StatusOK},
+		{name: "CIDR range match", remoteAddr: "10.1.2.3:1234", status: http.StatusOK},
+		{name: "IPv6 range match", remoteAddr: "[2001:db8::1]:1234", status: http.StatusOK},
+		{name: "IPv4-mapped IPv6 match", remoteAddr: "[::ffff:10.1.2.3]:1234", status: http.StatusOK},
+		{name: "deny priority", remoteAddr: "10.0.0.13:1234", status: http.StatusForbidden},
+		{name: "not allowed", remoteAddr: "192.0.2.8:1234", status: http.StatusForbidden},
+		{name: "invalid address", remoteAddr: "unknown", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := request(handler, tt.remoteAddr, nil); status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+		})
+	}
+
+	t.Run("deny only", func(t *testing.T) {
+		handler := NewIPFilterMiddleware(nil, []string{"203.0.113.0/24"}, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})(textHandler("metrics"))
+
+		if status := request(handler, "198.51.100.1:1234", nil); status != http.StatusOK {
+			t.Fatalf("status = %d, want %d", status, http.StatusOK)
+		}
+
+		if status := request(handler, "203.0.113.9:1234", nil); status != http.StatusTeapot {
+			t.Fatalf("status = %d, want the onDeny status %d", status, http.StatusTeapot)
+		}
+	})
+
+	t.Run("forwarded headers", func(t *testing.T) {
+		cfg := IPFilterConfig{Allow: []string{"192.0.2.7"}}
+		untrusted := NewIPFilterMiddlewareWithConfig(cfg)(textHandler("metrics"))
+		cfg.TrustForwardedHeaders = true
+		trusted := NewIPFilterMiddlewareWithConfig(cfg)(textHandler("metrics"))
+
+		tests := []struct {
+			headers map[string]string
+			handler http.HandlerFunc
+			status  int
+		}{
+			{headers: map[string]string{"X-Forwarded-For": "192.0.2.7, 10.0.0.1"}, handler: trusted, status: http.StatusOK},
+			{headers: map[string]string{"X-Real-IP": "192.0.2.7"}, handler: trusted, status: http.StatusOK},
+			{headers: map[string]string{"X-Forwarded-For": "192.0.2.8"}, handler: trusted, status: http.StatusForbidden},
+			{headers: map[string]string{"X-Forwarded-For": "192.0.2.7"}, handler: untrusted, status: http.StatusForbidden},
+		}
+
+		for _, tt := range tests {
+			if status := request(tt.handler, "10.0.0.1:1234", tt.headers); status != tt.status {
+				t.Errorf("headers %v status = %d, want %d", tt.headers, status, tt.status)
+			}
+		}
+	})
+
+	t.Run("invalid entries", func(t *testing.T) {
+		assertPanics(t, `invalid CIDR range "10.0.0.0/33"`, func() {
+			NewIPFilterMiddleware([]string{"10.0.0.0/33"}, nil, nil)
+		})
+
+		assertPanics(t, `invalid IP "localhost"`, func() {
+			NewIPFilterMiddleware(nil, []string{"localhost"}, nil)
+		})
+	})
+}
@@ -0,0 +1,13 @@
+package supermuxer
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSON writes the value as a JSON response with the status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,71 @@
+package supermuxer
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// jsonpCallback matches the callback names that cannot inject JavaScript, e.g. 'handleUsers' or 'app.users.load'.
+var jsonpCallback = regexp.MustCompile(`^[a-zA-Z_$][a-zA-Z0-9_$.]*$`)
+
+// NewJSONPMiddleware creates a middleware that wraps the JSON responses of the next handler as JSONP, like
+// 'callback({...});', with the 'Content-Type: text/javascript' header, for the requests with the callbackParam
+// query parameter. Callback names other than JavaScript identifiers and their properties get 400, and are not
+// passed to the next handler. The requests without the query parameter and the other responses are unchanged.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewJSONPMiddleware("callback")).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request 'GET /users?callback=showUsers'
+//		with the response 'showUsers([...]);'
+func NewJSONPMiddleware(callbackParam string) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if !query.Has(callbackParam) {
+				next(w, r)
+				return
+			}
+
+			callback := query.Get(callbackParam)
+			if !jsonpCallback.MatchString(callback) {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			bw := newBufferWriter(w)
+			next(bw, r)
+
+			if mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type")); mediaType != "application/json" {
+				bw.flush()
+				return
+			}
+
+			// The trailing newline written by json.Encoder is dropped, so the call ends the response.
+			data := bytes.TrimSpace(bw.body.Bytes())
+			body := make([]byte, 0, len(callback)+len(data)+3)
+			body = append(body, callback...)
+			body = append(body, '(')
+			body = append(body, data...)
+			body = append(body, ");"...)
+
+			bw.body.Reset()
+			bw.body.Write(body)
+
+			header := w.Header()
+			header.Set("Content-Type", "text/javascript; charset=utf-8")
+			header.Set("X-Content-Type-Options", "nosniff")
+			header.Set("Content-Length", strconv.Itoa(len(body)))
+
+			bw.flush()
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestJSONPMiddleware(t *testing.T) {
+	jsonHandler := func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusCreated, []string{"ada", "grace"})
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		handler         http.HandlerFunc
+		wantStatus      int
+		wantBody        string
+		wantContentType string
+	}{
+		{
+			name:            "wrapping",
+			query:           "?callback=showUsers",
+			handler:         jsonHandler,
+			wantStatus:      http.StatusCreated,
+			wantBody:        `showUsers(["ada","grace"]);`,
+			wantContentType: "text/javascript; charset=utf-8",
+		},
+		{
+			name:            "callback property",
+			query:           "?callback=app.users_%241.load",
+			handler:         jsonHandler,
+			wantStatus:      http.StatusCreated,
+			wantBody:        `app.users_$1.load(["ada","grace"]);`,
+			wantContentType: "text/javascript; charset=utf-8",
+		},
+		{
+			name:            "absent callback",
+			handler:         jsonHandler,
+			wantStatus:      http.StatusCreated,
+			wantBody:        "[\"ada\",\"grace\"]\n",
+			wantContentType: "application/json",
+		},
+		{
+			name:            "response other than JSON",
+			query:           "?callback=showUsers",
+			handler:         textHandler("ada, grace"),
+			wantStatus:      http.StatusOK,
+			wantBody:        "ada, grace",
+			wantContentType: "text/plain; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewJSONPMiddleware("callback")(tt.handler)
+			rec := serve(handler, http.MethodGet, "/users"+tt.query)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if rec.Body.String() != tt.wantBody {
+				t.Fatalf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Fatalf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+
+			if tt.wantContentType == "text/javascript; charset=utf-8" {
+				if got := rec.Header().Get("Content-Length"); got != strconv.Itoa(len(tt.wantBody)) {
+					t.Fatalf("Content-Length = %q, want %d", got, len(tt.wantBody))
+				}
+
+				if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+					t.Fatalf("X-Content-Type-Options = %q, want %q", got, "nosniff")
+				}
+			}
+		})
+	}
+
+	for _, callback := range []string{"", "1users", "alert(1)//", "show users", "showUsers;alert", "</script>"} {
+		t.Run("invalid callback "+strconv.Quote(callback), func(t *testing.T) {
+			handler := NewJSONPMiddleware("callback")(func(http.ResponseWriter, *http.Request) {
+				t.Fatal("handler called for an invalid callback")
+			})
+
+			if rec := serve(handler, http.MethodGet, "/users?callback="+url.QueryEscape(callback)); rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
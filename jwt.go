@@ -0,0 +1,215 @@
+package supermuxer
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// JWTConfig configures the middleware created by NewJWTMiddleware.
+type JWTConfig struct {
+	// SecretKey verifies the HS256, HS384 and HS512 algorithms.
+	SecretKey []byte
+	// PublicKey verifies the RS256, RS384 and RS512 algorithms. It must be a *rsa.PublicKey.
+	PublicKey crypto.PublicKey
+	// Algorithm is the only algorithm accepted, so tokens cannot pick a weaker one.
+	// Defaults to HS256 when SecretKey is set, otherwise RS256.
+	Algorithm string
+	// ClaimsContextKey is the request context key of the claims. Defaults to a package key.
+	ClaimsContextKey any
+	// TokenExtractor reads the token from the request. Defaults to the 'Authorization: Bearer <token>' header.
+	TokenExtractor func(*http.Request) (string, error)
+}
+
+type jwtClaimsContextKey struct{}
+
+var (
+	jwtHMACHashes = map[string]func() hash.Hash{
+		"HS256": sha256.New,
+		"HS384": sha512.New384,
+		"HS512": sha512.New,
+	}
+
+	jwtRSAHashes = map[string]crypto.Hash{
+		"RS256": crypto.SHA256,
+		"RS384": crypto.SHA384,
+		"RS512": crypto.SHA512,
+	}
+)
+
+// NewJWTMiddleware creates a middleware that validates the JSON Web Token of every request, including its signature,
+// 'exp' and 'nbf' claims. The claims are stored as a map[string]any in the request context under ClaimsContextKey,
+// readable with JWTClaimsFromContext. Requests with a missing or invalid token get 401.
+// It panics if the algorithm is unsupported or its key is missing.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	jwt := supermuxer.NewJWTMiddleware(supermuxer.JWTConfig{SecretKey: secret})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(jwt).Get("/me", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /me'
+//		responding with 401 when the bearer token is missing or invalid
+func NewJWTMiddleware(cfg JWTConfig) MiddlewareFunc {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = "HS256"
+		if len(cfg.SecretKey) == 0 {
+			cfg.Algorithm = "RS256"
+		}
+	}
+
+	if cfg.ClaimsContextKey == nil {
+		cfg.ClaimsContextKey = jwtClaimsContextKey{}
+	}
+
+	if cfg.TokenExtractor == nil {
+		cfg.TokenExtractor = bearerToken
+	}
+
+	verify := newJWTVerifier(cfg)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			token, err := cfg.TokenExtractor(r)
+			if err == nil {
+				var claims map[string]any
+				if claims, err = parseJWT(token, cfg.Algorithm, verify); err == nil {
+					next(w, r.WithContext(context.WithValue(r.Context(), cfg.ClaimsContextKey, claims)))
+					return
+				}
+			}
+
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		}
+	}
+}
+
+// JWTClaimsFromContext returns the claims stored under the key by the middleware created by NewJWTMiddleware.
+// A nil key reads the claims stored under the default key. The claims are stored as a map[string]any.
+func JWTClaimsFromContext[T any](ctx context.Context, key any) (T, bool) {
+	if key == nil {
+		key = jwtClaimsContextKey{}
+	}
+
+	claims, ok := ctx.Value(key).(T)
+	return claims, ok
+}
+
+// bearerToken reads the token of the 'Authorization: Bearer <token>' header.
+func bearerToken(r *http.Request) (string, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", errors.New("supermuxer: missing bearer token")
+	}
+
+	return token, nil
+}
+
+// newJWTVerifier creates the function that verifies the signature of the signed part of a token.
+func newJWTVerifier(cfg JWTConfig) func(signed []byte, signature []byte) error {
+	if newHash, ok := jwtHMACHashes[cfg.Algorithm]; ok {
+		if len(cfg.SecretKey) == 0 {
+			panic(fmt.Sprintf("supermuxer: JWT algorithm %q requires a secret key", cfg.Algorithm))
+		}
+
+		return func(signed []byte, signature []byte) error {
+			mac := hmac.New(newHash, cfg.SecretKey)
+			mac.Write(signed)
+
+			if !hmac.Equal(signature, mac.Sum(nil)) {
+				return errors.New("supermuxer: invalid JWT signature")
+			}
+
+			return nil
+		}
+	}
+
+	if hashName, ok := jwtRSAHashes[cfg.Algorithm]; ok {
+		publicKey, ok := cfg.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			panic(fmt.Sprintf("supermuxer: JWT algorithm %q requires a *rsa.PublicKey", cfg.Algorithm))
+		}
+
+		return func(signed []byte, signature []byte) error {
+			digest := hashName.New()
+			digest.Write(signed)
+
+			return rsa.VerifyPKCS1v15(publicKey, hashName, digest.Sum(nil), signature)
+		}
+	}
+
+	panic(fmt.Sprintf("supermuxer: unsupported JWT algorithm %q", cfg.Algorithm))
+}
+
+// parseJWT verifies the token and returns its claims.
+func parseJWT(token string, algorithm string, verify func(signed []byte, signature []byte) error) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("supermuxer: malformed JWT")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	if header.Alg != algorithm {
+		return nil, fmt.Errorf("supermuxer: unexpected JWT algorithm %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("supermuxer: malformed JWT signature: %w", err)
+	}
+
+	if err := verify([]byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	now := float64(time.Now().Unix())
+
+	if exp, ok := claims["exp"].(float64); ok && now >= exp {
+		return nil, errors.New("supermuxer: expired JWT")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now < nbf {
+		return nil, errors.New("supermuxer: JWT not valid yet")
+	}
+
+	return claims, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("supermuxer: malformed JWT: %w", err)
+	}
+
+	if err := json.Unmarshal(decoded, v); err != nil {
+		return fmt.Errorf("supermuxer: malformed JWT: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package supermuxer
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// signJWT creates a token with the claims, signed by sign for the algorithm.
+func signJWT(t *testing.T, algorithm string, claims map[string]any, sign func(signed []byte) []byte) string {
+	t.Helper()
+
+	header, err := json.Marshal(map[string]string{"alg": algorithm, "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	secret := []byte("jwt-secret")
+	signHS256 := func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signRS256 := func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signature
+	}
+
+	echoSubject := func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := JWTClaimsFromContext[map[string]any](r.Context(), nil)
+		if !ok {
+			http.Error(w, "missing claims", http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(claims["sub"].(string)))
+	}
+
+	hs256 := NewJWTMiddleware(JWTConfig{SecretKey: secret})(echoSubject)
+	rs256 := NewJWTMiddleware(JWTConfig{PublicKey: &privateKey.PublicKey})(echoSubject)
+
+	request := func(handler http.HandlerFunc, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		return rec
+	}
+
+	valid := map[string]any{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()}
+	hsToken := signJWT(t, "HS256", valid, signHS256)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		header  string
+		status  int
+	}{
+		{name: "HS256", handler: hs256, header: "Bearer " + hsToken, status: http.StatusOK},
+		{name: "RS256", handler: rs256, header: "Bearer " + signJWT(t, "RS256", valid, signRS256), status: http.StatusOK},
+		{name: "lowercase scheme", handler: hs256, header: "bearer " + hsToken, status: http.StatusOK},
+		{name: "missing token", handler: hs256, status: http.StatusUnauthorized},
+		{name: "other scheme", handler: hs256, header: "Basic " + hsToken, status: http.StatusUnauthorized},
+		{name: "malformed token", handler: hs256, header: "Bearer not-a-token", status: http.StatusUnauthorized},
+		{name: "expired token", handler: hs256, header: "Bearer " + signJWT(t, "HS256", map[string]any{"sub": "user-1", "exp": time.Now().Add(-time.Minute).Unix()}, signHS256), status: http.StatusUnauthorized},
+		{name: "token not valid yet", handler: hs256, header: "Bearer " + signJWT(t, "HS256", map[string]any{"sub": "user-1", "nbf": time.Now().Add(time.Hour).Unix()}, signHS256), status: http.StatusUnauthorized},
+		{name: "other secret", handler: hs256, header: "Bearer " + signJWT(t, "HS256", valid, func(signed []byte) []byte {
+			mac := hmac.New(sha256.New, []byte("other-secret"))
+			mac.Write(signed)
+			return mac.Sum(nil)
+		}), status: http.StatusUnauthorized},
+		{name: "other algorithm", handler: rs256, header: "Bearer " + hsToken, status: http.StatusUnauthorized},
+		{name: "none algorithm", handler: hs256, header: "Bearer " + signJWT(t, "none", valid, func([]byte) []byte { return nil }), status: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := request(tt.handler, tt.header)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if tt.status == http.StatusOK && rec.Body.String() != "user-1" {
+				t.Fatalf("subject = %q, want %q", rec.Body.String(), "user-1")
+			}
+
+			if tt.status == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != `Bearer error="invalid_token"` {
+				t.Fatalf("WWW-Authenticate = %q, want the invalid_token error", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	t.Run("extractor and context key", func(t *testing.T) {
+		type claimsKey struct{}
+
+		handler := NewJWTMiddleware(JWTConfig{
+			SecretKey:        secret,
+			ClaimsContextKey: claimsKey{},
+			TokenExtractor: func(r *http.Request) (string, error) {
+				cookie, err := r.Cookie("token")
+				if err != nil {
+					return "", errors.New("missing cookie")
+				}
+				return cookie.Value, nil
+			},
+		})(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ := JWTClaimsFromContext[map[string]any](r.Context(), claimsKey{})
+			w.Write([]byte(claims["sub"].(string)))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.AddCookie(&http.Cookie{Name: "token", Value: hsToken})
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Code != http.StatusOK || rec.Body.String() != "user-1" {
+			t.Fatalf("response = %d %q, want 200 %q", rec.Code, rec.Body.String(), "user-1")
+		}
+	})
+
+	t.Run("invalid configs", func(t *testing.T) {
+		assertPanics(t, `JWT algorithm "HS512" requires a secret key`, func() {
+			NewJWTMiddleware(JWTConfig{Algorithm: "HS512"})
+		})
+
+		assertPanics(t, `JWT algorithm "RS256" requires a *rsa.PublicKey`, func() {
+			NewJWTMiddleware(JWTConfig{})
+		})
+
+		assertPanics(t, `unsupported JWT algorithm "ES256"`, func() {
+			NewJWTMiddleware(JWTConfig{Algorithm: "ES256", SecretKey: secret})
+		})
+	})
+}
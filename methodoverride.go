@@ -0,0 +1,51 @@
+package supermuxer
+
+import (
+	"net/http"
+	"strings"
+)
+
+// methodOverrides are the methods that the middleware created by NewHTTPMethodOverrideMiddleware can tunnel.
+var methodOverrides = map[string]bool{
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// NewHTTPMethodOverrideMiddleware creates a middleware that replaces the method of the 'POST' requests with the one
+// in the 'X-HTTP-Method-Override' header or, when the header is missing, the paramName query parameter, for the
+// clients that cannot send 'PUT', 'PATCH' or 'DELETE' requests, such as HTML forms. An empty paramName only reads
+// the header. Other methods are ignored.
+//
+// Routes are matched before their middlewares run, so the middleware must wrap the whole router, as in the example,
+// for the overridden method to select the route.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares or to wrap the router.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.Delete("/users/{id}", handler)
+//	server := supermuxer.NewHTTPMethodOverrideMiddleware("_method")(superRouter.ServeHTTP)
+//
+//	# Result: supermuxer configuration to handle the request 'POST /users/42?_method=DELETE'
+//		for the endpoint 'DELETE /users/{id}'
+func NewHTTPMethodOverrideMiddleware(paramName string) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				method := r.Header.Get("X-HTTP-Method-Override")
+				if method == "" && paramName != "" {
+					method = r.URL.Query().Get(paramName)
+				}
+
+				if method = strings.ToUpper(method); methodOverrides[method] {
+					r.Method = method
+				}
+			}
+
+			next(w, r)
+		}
+	}
+}
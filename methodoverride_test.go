@@ -0,0 +1,63 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPMethodOverrideMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	superRouter := New(mux)
+	superRouter.Post("/users/{id}", textHandler("POST"))
+	superRouter.Put("/users/{id}", textHandler("PUT"))
+	superRouter.Patch("/users/{id}", textHandler("PATCH"))
+	superRouter.Delete("/users/{id}", textHandler("DELETE"))
+	superRouter.Get("/users/{id}", textHandler("GET"))
+
+	handler := NewHTTPMethodOverrideMiddleware("_method")(mux.ServeHTTP)
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		override string
+		want     string
+	}{
+		{name: "header", method: http.MethodPost, target: "/users/1", override: "DELETE", want: "DELETE"},
+		{name: "lowercase header", method: http.MethodPost, target: "/users/1", override: "patch", want: "PATCH"},
+		{name: "query parameter", method: http.MethodPost, target: "/users/1?_method=PUT", want: "PUT"},
+		{name: "header before query parameter", method: http.MethodPost, target: "/users/1?_method=PUT", override: "DELETE", want: "DELETE"},
+		{name: "unsupported override", method: http.MethodPost, target: "/users/1", override: "GET", want: "POST"},
+		{name: "no override", method: http.MethodPost, target: "/users/1", want: "POST"},
+		{name: "not a POST request", method: http.MethodGet, target: "/users/1", override: "DELETE", want: "GET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.override != "" {
+				req.Header.Set("X-HTTP-Method-Override", tt.override)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Body.String() != tt.want {
+				t.Fatalf("handled method = %q, want %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+
+	t.Run("header only", func(t *testing.T) {
+		var method string
+		handler := NewHTTPMethodOverrideMiddleware("")(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+		})
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/1?_method=DELETE", nil))
+		if method != http.MethodPost {
+			t.Fatalf("method = %q, want %q", method, http.MethodPost)
+		}
+	})
+}
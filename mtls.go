@@ -0,0 +1,64 @@
+package supermuxer
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"slices"
+)
+
+// MTLSConfig configures the middleware created by NewMTLSMiddleware.
+type MTLSConfig struct {
+	// RequireClientCert rejects the requests without a client certificate with 401.
+	RequireClientCert bool
+	// AllowedCommonNames lists the subject common names of the accepted client certificates. Empty accepts any.
+	AllowedCommonNames []string
+}
+
+type clientCertContextKey struct{}
+
+// NewMTLSMiddleware creates a middleware that authenticates the clients by their TLS certificate, stored in the
+// request context, readable with ClientCertFromContext. Certificates whose subject common name is not allowed
+// are rejected with 403. The certificates are verified by the server, which must request them in its tls.Config,
+// e.g. with 'ClientAuth: tls.VerifyClientCertIfGiven' and the client CAs.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	mtls := supermuxer.NewMTLSMiddleware(supermuxer.MTLSConfig{RequireClientCert: true, AllowedCommonNames: []string{"billing"}})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(mtls).Post("/invoices", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /invoices'
+//		only from the clients with a certificate for 'billing'
+func NewMTLSMiddleware(cfg MTLSConfig) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+				if cfg.RequireClientCert {
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+
+				next(w, r)
+				return
+			}
+
+			cert := r.TLS.PeerCertificates[0]
+			if len(cfg.AllowedCommonNames) > 0 && !slices.Contains(cfg.AllowedCommonNames, cert.Subject.CommonName) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), clientCertContextKey{}, cert)))
+		}
+	}
+}
+
+// ClientCertFromContext returns the client certificate stored by the middleware created by NewMTLSMiddleware.
+func ClientCertFromContext(ctx context.Context) (*x509.Certificate, bool) {
+	cert, ok := ctx.Value(clientCertContextKey{}).(*x509.Certificate)
+	return cert, ok
+}
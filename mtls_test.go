@@ -0,0 +1,156 @@
+package supermuxer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newClientCertificates creates a CA and a client certificate signed by it for each common name.
+func newClientCertificates(t *testing.T, commonNames ...string) (*x509.CertPool, map[string]tls.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca, _ := x509.ParseCertificate(caDER)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+
+	certs := map[string]tls.Certificate{}
+	for i, commonName := range commonNames {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(i + 2)),
+			Subject:      pkix.Name{CommonName: commonName},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		certs[commonName] = tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+	}
+
+	return pool, certs
+}
+
+func TestMTLSMiddleware(t *testing.T) {
+	pool, certs := newClientCertificates(t, "billing-service", "unknown-service")
+
+	mux := http.NewServeMux()
+	superRouter := New(mux)
+	superRouter.SubGroup("/required").AddMiddlewares(NewMTLSMiddleware(MTLSConfig{
+		RequireClientCert:  true,
+		AllowedCommonNames: []string{"billing-service"},
+	})).Get("/invoices", func(w http.ResponseWriter, r *http.Request) {
+		cert, ok := ClientCertFromContext(r.Context())
+		if !ok {
+			t.Error("ClientCertFromContext() ok = false, want true")
+			return
+		}
+		w.Write([]byte(cert.Subject.CommonName))
+	})
+	superRouter.SubGroup("/optional").AddMiddlewares(NewMTLSMiddleware(MTLSConfig{})).Get("/invoices", func(w http.ResponseWriter, r *http.Request) {
+		if cert, ok := ClientCertFromContext(r.Context()); ok {
+			w.Write([]byte(cert.Subject.CommonName))
+			return
+		}
+		w.Write([]byte("anonymous"))
+	})
+
+	server := httptest.NewUnstartedServer(mux)
+	server.TLS = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: pool}
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(t *testing.T, path string, certName string) (int, string) {
+		t.Helper()
+
+		transport := server.Client().Transport.(*http.Transport).Clone()
+		if certName != "" {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{certs[certName]}
+		}
+
+		client := &http.Client{Transport: transport}
+		defer client.CloseIdleConnections()
+
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		certName string
+		status   int
+		body     string
+	}{
+		{name: "allowed certificate", path: "/required/invoices", certName: "billing-service", status: http.StatusOK, body: "billing-service"},
+		{name: "not allowed certificate", path: "/required/invoices", certName: "unknown-service", status: http.StatusForbidden},
+		{name: "missing required certificate", path: "/required/invoices", status: http.StatusUnauthorized},
+		{name: "optional certificate", path: "/optional/invoices", certName: "unknown-service", status: http.StatusOK, body: "unknown-service"},
+		{name: "missing optional certificate", path: "/optional/invoices", status: http.StatusOK, body: "anonymous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := get(t, tt.path, tt.certName)
+			if status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+
+			if tt.body != "" && body != tt.body {
+				t.Fatalf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+
+	t.Run("plain HTTP", func(t *testing.T) {
+		rec := serve(mux, http.MethodGet, "/required/invoices")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package supermuxer
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type negotiatedContentTypeContextKey struct{}
+
+// NewContentNegotiationMiddleware creates a middleware that selects, for the 'Accept' request header, the best of the
+// supported media types, e.g. 'application/json', following RFC 7231 quality factors and wildcards. Ties are won by
+// the first supported type, which is also selected when the header is missing. The selection is stored in the request
+// context, readable with NegotiatedContentType, and requests accepting none of the types get 406.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	negotiation := supermuxer.NewContentNegotiationMiddleware([]string{"application/json", "text/csv"})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(negotiation).Get("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /reports'
+//		with 'text/csv' selected for the 'Accept: text/csv, application/json;q=0.5' header
+func NewContentNegotiationMiddleware(supported []string) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept")
+
+			contentType, ok := negotiateContentType(r.Header.Get("Accept"), supported)
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), negotiatedContentTypeContextKey{}, contentType)))
+		}
+	}
+}
+
+// NegotiatedContentType returns the media type selected by the middleware created by NewContentNegotiationMiddleware,
+// or an empty string if there is none.
+func NegotiatedContentType(ctx context.Context) string {
+	contentType, _ := ctx.Value(negotiatedContentTypeContextKey{}).(string)
+	return contentType
+}
+
+// negotiateContentType selects the supported media type with the highest quality in the 'Accept' header.
+func negotiateContentType(accept string, supported []string) (string, bool) {
+	if len(supported) == 0 {
+		return "", false
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return supported[0], true
+	}
+
+	best, bestQuality := "", 0.0
+
+	for _, contentType := range supported {
+		if q := acceptQuality(accept, contentType); q > bestQuality {
+			best, bestQuality = contentType, q
+		}
+	}
+
+	return best, bestQuality > 0
+}
+
+// acceptQuality returns the quality of the most specific media range of the 'Accept' header matching the media type.
+func acceptQuality(accept string, contentType string) float64 {
+	mainType, subType, _ := strings.Cut(strings.ToLower(contentType), "/")
+	quality, specificity := 0.0, -1
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+		rangeMain, rangeSub, _ := strings.Cut(strings.ToLower(strings.TrimSpace(mediaRange)), "/")
+
+		var rangeSpecificity int
+		switch {
+		case rangeMain == mainType && rangeSub == subType:
+			rangeSpecificity = 2
+		case rangeMain == mainType && rangeSub == "*":
+			rangeSpecificity = 1
+		case rangeMain == "*" && rangeSub == "*":
+			rangeSpecificity = 0
+		default:
+			continue
+		}
+
+		if rangeSpecificity <= specificity {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		quality, specificity = q, rangeSpecificity
+	}
+
+	return quality
+}
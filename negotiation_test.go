@@ -0,0 +1,64 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContentNegotiationMiddleware(t *testing.T) {
+	handler := NewContentNegotiationMiddleware([]string{"application/json", "application/xml", "text/html"})(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(NegotiatedContentType(r.Context())))
+	})
+
+	tests := []struct {
+		name   string
+		accept string
+		status int
+		want   string
+	}{
+		{name: "missing header", status: http.StatusOK, want: "application/json"},
+		{name: "exact match", accept: "text/html", status: http.StatusOK, want: "text/html"},
+		{name: "wildcard", accept: "*/*", status: http.StatusOK, want: "application/json"},
+		{name: "subtype wildcard", accept: "text/*", status: http.StatusOK, want: "text/html"},
+		{name: "quality ordering", accept: "application/json;q=0.5, application/xml;q=0.9, text/html;q=0.1", status: http.StatusOK, want: "application/xml"},
+		{name: "tie won by the first supported", accept: "text/html, application/xml", status: http.StatusOK, want: "application/xml"},
+		{name: "specific range over wildcard", accept: "*/*;q=0.8, application/json;q=0", status: http.StatusOK, want: "application/xml"},
+		{name: "case insensitive", accept: "Application/XML", status: http.StatusOK, want: "application/xml"},
+		{name: "no match", accept: "image/png", status: http.StatusNotAcceptable},
+		{name: "refused", accept: "application/json;q=0, application/xml;q=0, text/html;q=0", status: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if tt.status == http.StatusOK && rec.Body.String() != tt.want {
+				t.Fatalf("negotiated type = %q, want %q", rec.Body.String(), tt.want)
+			}
+
+			if rec.Header().Get("Vary") != "Accept" {
+				t.Fatalf("Vary = %q, want %q", rec.Header().Get("Vary"), "Accept")
+			}
+		})
+	}
+
+	t.Run("no supported types", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewContentNegotiationMiddleware(nil)(textHandler("ok"))(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusNotAcceptable {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotAcceptable)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package supermuxer
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// OpenAPIInfo is the info object of the OpenAPI document created by GenerateOpenAPI.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type (
+	openAPIDocument struct {
+		OpenAPI string                                 `json:"openapi"`
+		Info    OpenAPIInfo                            `json:"info"`
+		Paths   map[string]map[string]openAPIOperation `json:"paths"`
+	}
+
+	openAPIOperation struct {
+		Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+		RequestBody *openAPIBody               `json:"requestBody,omitempty"`
+		Responses   map[string]openAPIResponse `json:"responses"`
+	}
+
+	openAPIParameter struct {
+		Name     string        `json:"name"`
+		In       string        `json:"in"`
+		Required bool          `json:"required"`
+		Schema   openAPISchema `json:"schema"`
+	}
+
+	openAPIBody struct {
+		Content map[string]openAPIMediaType `json:"content"`
+	}
+
+	openAPIResponse struct {
+		Description string                      `json:"description"`
+		Content     map[string]openAPIMediaType `json:"content"`
+	}
+
+	openAPIMediaType struct {
+		Schema openAPISchema `json:"schema"`
+	}
+
+	openAPISchema struct {
+		Type string `json:"type"`
+	}
+)
+
+// openAPIMethods are the methods that OpenAPI 3.0 path items can describe.
+var openAPIMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+func (r *router) GenerateOpenAPI(info OpenAPIInfo) ([]byte, error) {
+	jsonContent := map[string]openAPIMediaType{"application/json": {Schema: openAPISchema{Type: "object"}}}
+	doc := openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]map[string]openAPIOperation{},
+	}
+
+	for _, route := range *r.routes {
+		if !openAPIMethods[route.Method] {
+			continue
+		}
+
+		path, params := openAPIPath(route.Pattern)
+		operation := openAPIOperation{
+			Responses: map[string]openAPIResponse{
+				"default": {Description: "Default response", Content: jsonContent},
+			},
+		}
+
+		for _, param := range params {
+			operation.Parameters = append(operation.Parameters, openAPIParameter{
+				Name:     param,
+				In:       "path",
+				Required: true,
+				Schema:   openAPISchema{Type: "string"},
+			})
+		}
+
+		switch route.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			operation.RequestBody = &openAPIBody{Content: jsonContent}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]openAPIOperation{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// openAPIPath converts the http.ServeMux pattern to an OpenAPI path, e.g. '/files/{path...}' to '/files/{path}',
+// and returns the names of its wildcards.
+func openAPIPath(pattern string) (string, []string) {
+	pattern = strings.TrimSuffix(pattern, "{$}")
+
+	var params []string
+	segments := strings.Split(pattern, "/")
+
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := strings.TrimSuffix(segment[1:len(segment)-1], "...")
+			segments[i] = "{" + name + "}"
+			params = append(params, name)
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
@@ -0,0 +1,95 @@
+package supermuxer
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestGenerateOpenAPI(t *testing.T) {
+	superRouter := New(http.NewServeMux())
+	superRouter.Get("/health", textHandler("ok"))
+	superRouter.Connect("/tunnel", textHandler("ok"))
+
+	api := superRouter.SubGroup("/api")
+	api.Get("/users/{id}", textHandler("user"))
+	api.Put("/users/{id}", textHandler("user"))
+	api.Post("/users", textHandler("created"))
+	api.Get("/files/{path...}", textHandler("file"))
+	api.Get("/{$}", textHandler("index"))
+
+	spec, err := superRouter.GenerateOpenAPI(OpenAPIInfo{Title: "Users API", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("GenerateOpenAPI() error = %v", err)
+	}
+
+	var doc struct {
+		OpenAPI string      `json:"openapi"`
+		Info    OpenAPIInfo `json:"info"`
+		Paths   map[string]map[string]struct {
+			Parameters []struct {
+				Name     string `json:"name"`
+				In       string `json:"in"`
+				Required bool   `json:"required"`
+			} `json:"parameters"`
+			RequestBody *struct {
+				Content map[string]any `json:"content"`
+			} `json:"requestBody"`
+			Responses map[string]any `json:"responses"`
+		} `json:"paths"`
+	}
+
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if doc.OpenAPI != "3.0.3" || doc.Info.Title != "Users API" || doc.Info.Version != "1.0.0" {
+		t.Fatalf("document header = %q %+v, want 3.0.3 with the info", doc.OpenAPI, doc.Info)
+	}
+
+	wantPaths := map[string][]string{
+		"/health":           {"get"},
+		"/api/users/{id}":   {"get", "put"},
+		"/api/users":        {"post"},
+		"/api/files/{path}": {"get"},
+		"/api/":             {"get"},
+	}
+
+	if len(doc.Paths) != len(wantPaths) {
+		t.Fatalf("paths = %v, want %v", doc.Paths, wantPaths)
+	}
+
+	for path, methods := range wantPaths {
+		var got []string
+		for method := range doc.Paths[path] {
+			got = append(got, method)
+		}
+		slices.Sort(got)
+
+		if !slices.Equal(got, methods) {
+			t.Errorf("%s methods = %v, want %v", path, got, methods)
+		}
+	}
+
+	getUser := doc.Paths["/api/users/{id}"]["get"]
+	if len(getUser.Parameters) != 1 || getUser.Parameters[0].Name != "id" || getUser.Parameters[0].In != "path" || !getUser.Parameters[0].Required {
+		t.Fatalf("parameters = %+v, want the required 'id' path parameter", getUser.Parameters)
+	}
+
+	if getUser.RequestBody != nil {
+		t.Fatal("GET operation has a request body")
+	}
+
+	if _, ok := getUser.Responses["default"]; !ok {
+		t.Fatalf("responses = %v, want a default response", getUser.Responses)
+	}
+
+	if body := doc.Paths["/api/users"]["post"].RequestBody; body == nil || body.Content["application/json"] == nil {
+		t.Fatalf("POST request body = %+v, want a JSON body", body)
+	}
+
+	if params := doc.Paths["/api/files/{path}"]["get"].Parameters; len(params) != 1 || params[0].Name != "path" {
+		t.Fatalf("wildcard parameters = %+v, want 'path'", params)
+	}
+}
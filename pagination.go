@@ -0,0 +1,106 @@
+package supermuxer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// PaginationConfig configures the middleware created by NewPaginationMiddleware.
+type PaginationConfig struct {
+	// PageParam is the query parameter of the page number, starting at 1. Defaults to 'page'.
+	PageParam string
+	// PerPageParam is the query parameter of the page size. Defaults to 'per_page'.
+	PerPageParam string
+	// MaxPerPage is the maximum page size, to which bigger sizes are clamped. Defaults to 100.
+	MaxPerPage int
+	// DefaultPerPage is the page size of the requests without PerPageParam. Defaults to 20.
+	DefaultPerPage int
+}
+
+// PaginationParams is the page of a request parsed by the middleware created by NewPaginationMiddleware.
+type PaginationParams struct {
+	Page    int
+	PerPage int
+	// Offset is the number of items before the page, to be used with PerPage as the limit.
+	Offset int
+}
+
+type paginationContextKey struct{}
+
+// NewPaginationMiddleware creates a middleware that parses the page and page size query parameters of the requests,
+// storing them in the request context, readable with PaginationFromContext. The page size is clamped to MaxPerPage.
+// Requests with non-numeric, zero or negative values get 400 with the '{"error": "..."}' JSON body.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	pagination := supermuxer.NewPaginationMiddleware(supermuxer.PaginationConfig{MaxPerPage: 50})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(pagination).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users?page=2&per_page=50'
+//		with the second page of 50 users in the request context
+func NewPaginationMiddleware(cfg PaginationConfig) MiddlewareFunc {
+	if cfg.PageParam == "" {
+		cfg.PageParam = "page"
+	}
+
+	if cfg.PerPageParam == "" {
+		cfg.PerPageParam = "per_page"
+	}
+
+	if cfg.MaxPerPage <= 0 {
+		cfg.MaxPerPage = 100
+	}
+
+	if cfg.DefaultPerPage <= 0 {
+		cfg.DefaultPerPage = min(20, cfg.MaxPerPage)
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+
+			page, err := positiveQueryParam(query.Get(cfg.PageParam), cfg.PageParam, 1)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			perPage, err := positiveQueryParam(query.Get(cfg.PerPageParam), cfg.PerPageParam, cfg.DefaultPerPage)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			perPage = min(perPage, cfg.MaxPerPage)
+			params := PaginationParams{Page: page, PerPage: perPage, Offset: (page - 1) * perPage}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), paginationContextKey{}, params)))
+		}
+	}
+}
+
+// PaginationFromContext returns the page stored by the middleware created by NewPaginationMiddleware.
+func PaginationFromContext(ctx context.Context) (PaginationParams, bool) {
+	params, ok := ctx.Value(paginationContextKey{}).(PaginationParams)
+	return params, ok
+}
+
+// positiveQueryParam parses the value of the query parameter as a positive integer, or returns fallback if it is empty.
+func positiveQueryParam(value string, name string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("query parameter %q must be a positive integer, got %q", name, value)
+	}
+
+	return n, nil
+}
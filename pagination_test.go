@@ -0,0 +1,91 @@
+package supermuxer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPaginationMiddleware(t *testing.T) {
+	newHandler := func(cfg PaginationConfig, got *PaginationParams) http.HandlerFunc {
+		return NewPaginationMiddleware(cfg)(func(w http.ResponseWriter, r *http.Request) {
+			*got, _ = PaginationFromContext(r.Context())
+		})
+	}
+
+	tests := []struct {
+		name   string
+		cfg    PaginationConfig
+		target string
+		want   PaginationParams
+	}{
+		{name: "defaults", target: "/users", want: PaginationParams{Page: 1, PerPage: 20, Offset: 0}},
+		{name: "page and size", target: "/users?page=3&per_page=10", want: PaginationParams{Page: 3, PerPage: 10, Offset: 20}},
+		{name: "clamped size", target: "/users?page=2&per_page=500", want: PaginationParams{Page: 2, PerPage: 100, Offset: 100}},
+		{name: "configured maximum", cfg: PaginationConfig{MaxPerPage: 10}, target: "/users?per_page=50", want: PaginationParams{Page: 1, PerPage: 10}},
+		{name: "default size within the maximum", cfg: PaginationConfig{MaxPerPage: 5}, target: "/users", want: PaginationParams{Page: 1, PerPage: 5}},
+		{name: "configured default size", cfg: PaginationConfig{DefaultPerPage: 50}, target: "/users?page=2", want: PaginationParams{Page: 2, PerPage: 50, Offset: 50}},
+		{name: "custom parameters", cfg: PaginationConfig{PageParam: "p", PerPageParam: "limit"}, target: "/users?p=4&limit=5&page=9", want: PaginationParams{Page: 4, PerPage: 5, Offset: 15}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got PaginationParams
+			rec := httptest.NewRecorder()
+			newHandler(tt.cfg, &got)(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+
+			if got != tt.want {
+				t.Fatalf("params = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name   string
+		target string
+		param  string
+	}{
+		{name: "non-numeric page", target: "/users?page=two", param: "page"},
+		{name: "zero page", target: "/users?page=0", param: "page"},
+		{name: "negative page", target: "/users?page=-1", param: "page"},
+		{name: "non-numeric size", target: "/users?per_page=ten", param: "per_page"},
+		{name: "negative size", target: "/users?per_page=-5", param: "per_page"},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := NewPaginationMiddleware(PaginationConfig{})(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if rec.Code != http.StatusBadRequest || called {
+				t.Fatalf("status = %d, handler called = %v, want %d without calling the handler", rec.Code, called, http.StatusBadRequest)
+			}
+
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("json.Unmarshal(%q) error = %v", rec.Body.String(), err)
+			}
+
+			if !strings.Contains(body["error"], `"`+tt.param+`"`) {
+				t.Fatalf("error = %q, want it to name %q", body["error"], tt.param)
+			}
+		})
+	}
+
+	t.Run("without middleware", func(t *testing.T) {
+		if _, ok := PaginationFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+			t.Fatal("PaginationFromContext() ok = true, want false")
+		}
+	})
+}
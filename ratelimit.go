@@ -0,0 +1,173 @@
+package supermuxer
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitConfig configures the middleware created by NewRateLimitMiddleware.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate at which each key regains requests. math.Inf(1) disables the limit.
+	RequestsPerSecond float64
+	// BurstSize is the number of requests a key can make at once. Defaults to 1.
+	BurstSize int
+	// KeyFunc identifies who is limited. Defaults to the client IP.
+	KeyFunc func(*http.Request) string
+	// ExceededHandler handles the rejected requests. Defaults to 429 with the 'Retry-After' header.
+	ExceededHandler http.HandlerFunc
+}
+
+// tokenBucket allows bursts of up to burst requests, regaining rate requests per second.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// allow takes a token from the bucket, or returns how long to wait until one is available.
+func (b *tokenBucket) allow() (bool, time.Duration) {
+	if math.IsInf(b.rate, 1) {
+		return true, 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if b.rate > 0 {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	if b.rate <= 0 {
+		return false, time.Second
+	}
+
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// refilled reports whether the bucket regained all its tokens, so it is the same as a new one, or was not used
+// for maxIdle.
+func (b *tokenBucket) refilled(now time.Time, maxIdle time.Duration) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	idle := now.Sub(b.last)
+	return idle >= maxIdle || (b.rate > 0 && b.tokens+idle.Seconds()*b.rate >= b.burst)
+}
+
+// tokenBucketStore keeps the token buckets of the keys seen by a rate limit middleware. The buckets are swept
+// at most once every tokenBucketSweepInterval, removing those that refilled, so idle keys do not use memory.
+type tokenBucketStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+const (
+	// tokenBucketSweepInterval is the minimum time between two sweeps of a tokenBucketStore.
+	tokenBucketSweepInterval = time.Minute
+	// tokenBucketMaxIdle is the time after which an unused bucket is removed, even if it did not refill,
+	// e.g. when it never regains requests.
+	tokenBucketMaxIdle = time.Hour
+)
+
+func newTokenBucketStore() *tokenBucketStore {
+	return &tokenBucketStore{buckets: map[string]*tokenBucket{}, lastSweep: time.Now()}
+}
+
+// get returns the bucket of the key, creating it with the rate and burst if there is none.
+func (s *tokenBucketStore) get(key string, rate float64, burst int) *tokenBucket {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.Sub(s.lastSweep) >= tokenBucketSweepInterval {
+		for bucketKey, bucket := range s.buckets {
+			if bucket.refilled(now, tokenBucketMaxIdle) {
+				delete(s.buckets, bucketKey)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = newTokenBucket(rate, burst)
+		s.buckets[key] = bucket
+	}
+
+	return bucket
+}
+
+// retryAfterSeconds formats the duration for the 'Retry-After' header, rounding up to whole seconds.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
+
+// NewRateLimitMiddleware creates a middleware that limits the requests of each key, by default the client IP,
+// with a token bucket of BurstSize requests refilled at RequestsPerSecond.
+// The buckets are kept in memory for the keys seen, and removed once they refill, so the idle keys do not use memory.
+// The token bucket is implemented here rather than with golang.org/x/time/rate, as the supermuxer module only depends
+// on the standard library.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	limiter := supermuxer.NewRateLimitMiddleware(supermuxer.RateLimitConfig{RequestsPerSecond: 5, BurstSize: 10})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(limiter).Post("/login", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /login'
+//		responding with 429 once a client IP exceeds the limit
+func NewRateLimitMiddleware(cfg RateLimitConfig) MiddlewareFunc {
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = clientIP
+	}
+
+	buckets := newTokenBucketStore()
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := cfg.KeyFunc(r)
+
+			allowed, wait := buckets.get(key, cfg.RequestsPerSecond, cfg.BurstSize).allow()
+			if allowed {
+				next(w, r)
+				return
+			}
+
+			if cfg.ExceededHandler != nil {
+				cfg.ExceededHandler(w, r)
+				return
+			}
+
+			w.Header().Set("Retry-After", retryAfterSeconds(wait))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package supermuxer
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	request := func(handler http.HandlerFunc, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		return rec
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		handler := NewRateLimitMiddleware(RateLimitConfig{RequestsPerSecond: 0.1, BurstSize: 2})(textHandler("ok"))
+
+		for i := range 2 {
+			if rec := request(handler, "192.0.2.1:1234"); rec.Code != http.StatusOK {
+				t.Fatalf("request %d status = %d, want %d", i+1, rec.Code, http.StatusOK)
+			}
+		}
+
+		rec := request(handler, "192.0.2.1:5678")
+		if rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("status above the limit = %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+
+		if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "10" {
+			t.Fatalf("Retry-After = %q, want %q", retryAfter, "10")
+		}
+
+		if rec := request(handler, "192.0.2.2:1234"); rec.Code != http.StatusOK {
+			t.Fatalf("status of another client = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("exceeded handler", func(t *testing.T) {
+		handler := NewRateLimitMiddleware(RateLimitConfig{
+			RequestsPerSecond: 1,
+			KeyFunc:           func(r *http.Request) string { return r.Header.Get("X-API-Key") },
+			ExceededHandler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+		})(textHandler("ok"))
+
+		request(handler, "192.0.2.1:1234")
+		if rec := request(handler, "192.0.2.2:1234"); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		handler := NewRateLimitMiddleware(RateLimitConfig{RequestsPerSecond: math.Inf(1)})(textHandler("ok"))
+
+		for range 100 {
+			if rec := request(handler, "192.0.2.1:1234"); rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+		}
+	})
+}
+
+func TestTokenBucketStore(t *testing.T) {
+	store := newTokenBucketStore()
+
+	idle := store.get("idle", 1, 1)
+	idle.allow()
+	idle.last = time.Now().Add(-2 * time.Second)
+
+	busy := store.get("busy", 0.001, 2)
+	busy.allow()
+
+	stale := store.get("stale", 0, 1)
+	stale.allow()
+	stale.last = time.Now().Add(-tokenBucketMaxIdle)
+
+	store.lastSweep = time.Now().Add(-tokenBucketSweepInterval)
+	store.get("new", 1, 1)
+
+	for key, want := range map[string]bool{"idle": false, "busy": true, "stale": false, "new": true} {
+		if _, ok := store.buckets[key]; ok != want {
+			t.Errorf("bucket %q kept = %t, want %t", key, ok, want)
+		}
+	}
+
+	if store.get("busy", 0.001, 2) != busy {
+		t.Fatal("the bucket of a busy key was replaced")
+	}
+}
@@ -0,0 +1,102 @@
+package supermuxer
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+)
+
+type panicStackTraceContextKey struct{}
+
+// PanicRecovery creates a middleware that recovers from panics raised by the next handlers
+// and calls fn with the recovered value. When fn is nil, a plain 500 response is written instead.
+// Panics with http.ErrAbortHandler are re-raised, so the server can abort the response as usual.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.PanicRecovery(nil))
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		responding with 500 if the handler panics
+func PanicRecovery(fn func(http.ResponseWriter, *http.Request, any)) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				if fn == nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+
+				fn(w, r, v)
+			}()
+
+			next(w, r)
+		}
+	}
+}
+
+// NewRecoveryMiddleware works the same way as PanicRecovery, but also captures the stack trace of the panic.
+// The handler receives the request with the stack trace in its context, readable with PanicStackTrace,
+// e.g. to log it or to include it in the error responses of development environments.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	recovery := supermuxer.NewRecoveryMiddleware(func(w http.ResponseWriter, r *http.Request, v any) {
+//		slog.Error("panic", "value", v, "stack", string(supermuxer.PanicStackTrace(r.Context())))
+//		http.Error(w, "internal error", http.StatusInternalServerError)
+//	})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(recovery).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		logging the stack trace and responding with 500 if the handler panics
+func NewRecoveryMiddleware(handler func(http.ResponseWriter, *http.Request, any)) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				if handler == nil {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+
+				stack := debug.Stack()
+				handler(w, r.WithContext(context.WithValue(r.Context(), panicStackTraceContextKey{}, stack)), v)
+			}()
+
+			next(w, r)
+		}
+	}
+}
+
+// PanicStackTrace returns the stack trace captured by the middleware created by NewRecoveryMiddleware,
+// or nil if there is none.
+func PanicStackTrace(ctx context.Context) []byte {
+	stack, _ := ctx.Value(panicStackTraceContextKey{}).([]byte)
+	return stack
+}
@@ -0,0 +1,140 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	panicking := func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}
+
+	t.Run("default response", func(t *testing.T) {
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(PanicRecovery(nil)).Get("/users", panicking)
+
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		for range 2 {
+			resp, err := server.Client().Get(server.URL + "/users")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusInternalServerError {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusInternalServerError)
+			}
+		}
+	})
+
+	t.Run("custom handler", func(t *testing.T) {
+		var recovered any
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(PanicRecovery(func(w http.ResponseWriter, r *http.Request, v any) {
+			recovered = v
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})).Get("/users", panicking)
+
+		if rec := serve(mux, http.MethodGet, "/users"); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+
+		if recovered != "boom" {
+			t.Fatalf("recovered = %v, want %q", recovered, "boom")
+		}
+	})
+
+	t.Run("router option", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{PanicHandler: func(w http.ResponseWriter, r *http.Request, v any) {
+			w.WriteHeader(http.StatusTeapot)
+		}}).SubGroup("/api").Get("/users", panicking)
+
+		if rec := serve(mux, http.MethodGet, "/api/users"); rec.Code != http.StatusTeapot {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusTeapot)
+		}
+	})
+
+	t.Run("aborted handler", func(t *testing.T) {
+		handler := PanicRecovery(nil)(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Fatalf("recovered = %v, want http.ErrAbortHandler", v)
+			}
+		}()
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	panicking := func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}
+
+	t.Run("handler gets the value and the stack trace", func(t *testing.T) {
+		var recovered any
+		var stack []byte
+
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(NewRecoveryMiddleware(func(w http.ResponseWriter, r *http.Request, v any) {
+			recovered, stack = v, PanicStackTrace(r.Context())
+			w.WriteHeader(http.StatusInternalServerError)
+		})).Get("/users", panicking)
+
+		if rec := serve(mux, http.MethodGet, "/users"); rec.Code != http.StatusInternalServerError {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+		}
+
+		if recovered != "boom" {
+			t.Fatalf("recovered = %v, want %q", recovered, "boom")
+		}
+
+		if len(stack) == 0 {
+			t.Fatal("stack trace is empty")
+		}
+
+		if !strings.Contains(string(stack), "TestRecoveryMiddleware") {
+			t.Fatalf("stack trace does not include the panicking handler:\n%s", stack)
+		}
+	})
+
+	t.Run("default response", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewRecoveryMiddleware(nil)(panicking)(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("aborted handler", func(t *testing.T) {
+		handler := NewRecoveryMiddleware(func(w http.ResponseWriter, r *http.Request, v any) {
+			t.Fatal("handler called for http.ErrAbortHandler")
+		})(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})
+
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Fatalf("recovered = %v, want http.ErrAbortHandler", v)
+			}
+		}()
+
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	t.Run("without middleware", func(t *testing.T) {
+		if stack := PanicStackTrace(httptest.NewRequest(http.MethodGet, "/", nil).Context()); stack != nil {
+			t.Fatalf("PanicStackTrace() = %q, want nil", stack)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package supermuxer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordedRequest is a request, and the response to it, recorded by the middleware created by
+// NewRequestReplayMiddleware.
+type RecordedRequest struct {
+	Method       string
+	URL          string
+	Header       http.Header
+	Body         []byte
+	StatusCode   int
+	ResponseBody []byte
+	RecordedAt   time.Time
+}
+
+// ReplayStore keeps the requests recorded by the middleware created by NewRequestReplayMiddleware.
+// Implementations must be safe for concurrent use.
+type ReplayStore interface {
+	// Record stores the request, whose body can be read from r.Body, with the status code and body of its response.
+	Record(r *http.Request, statusCode int, body []byte) error
+	// Replay sends the recorded requests in the order they were recorded, closing the channel after the last one
+	// or once the context is done.
+	Replay(ctx context.Context) (<-chan RecordedRequest, error)
+}
+
+// InMemoryReplayStore is a ReplayStore that keeps the recorded requests in memory, without any limit,
+// so it is meant for tests and short recording sessions.
+type InMemoryReplayStore struct {
+	mu       sync.Mutex
+	requests []RecordedRequest
+}
+
+// NewInMemoryReplayStore creates an empty InMemoryReplayStore.
+func NewInMemoryReplayStore() *InMemoryReplayStore {
+	return &InMemoryReplayStore{}
+}
+
+// replayWriter keeps a copy of the response body written by the next handlers.
+type replayWriter struct {
+	*statusWriter
+	body bytes.Buffer
+}
+
+func (w *replayWriter) Write(b []byte) (int, error) {
+	n, err := w.statusWriter.Write(b)
+	w.body.Write(b[:n])
+
+	return n, err
+}
+
+func (s *InMemoryReplayStore) Record(r *http.Request, statusCode int, body []byte) error {
+	requestBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, RecordedRequest{
+		Method:       r.Method,
+		URL:          r.URL.RequestURI(),
+		Header:       r.Header.Clone(),
+		Body:         requestBody,
+		StatusCode:   statusCode,
+		ResponseBody: bytes.Clone(body),
+		RecordedAt:   time.Now(),
+	})
+
+	return nil
+}
+
+func (s *InMemoryReplayStore) Replay(ctx context.Context) (<-chan RecordedRequest, error) {
+	s.mu.Lock()
+	requests := make([]RecordedRequest, len(s.requests))
+	copy(requests, s.requests)
+	s.mu.Unlock()
+
+	ch := make(chan RecordedRequest)
+
+	go func() {
+		defer close(ch)
+
+		for _, request := range requests {
+			select {
+			case ch <- request:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// NewRequestReplayMiddleware creates a middleware that records every request, with its headers and body, and the
+// response of the next handler in the store once the handler returns, so they can be replayed with ReplayRequests.
+// The request body is buffered in memory, and the response body is copied while it is written. The recorded
+// headers include the credentials of the requests, such as 'Authorization' and 'Cookie', so the store must be kept
+// as safe as the production traffic. Errors of the store are logged with the default slog logger.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	store := supermuxer.NewInMemoryReplayStore()
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewRequestReplayMiddleware(store)).Post("/orders", handler)
+//	err := supermuxer.ReplayRequests(store, stagingURL)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /orders'
+//		recording every request to send it again to the staging environment
+func NewRequestReplayMiddleware(store ReplayStore) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			// The recorded copy is not affected by the changes the handler makes to the request.
+			recorded := r.Clone(r.Context())
+			recorded.Body = io.NopCloser(bytes.NewReader(body))
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			rw := &replayWriter{statusWriter: newStatusWriter(w)}
+			next(rw, r)
+
+			if err := store.Record(recorded, rw.Status(), rw.body.Bytes()); err != nil {
+				slog.Error("supermuxer: recording request", "error", err)
+			}
+		}
+	}
+}
+
+// ReplayRequests sends every request recorded in the store to the target with http.DefaultClient, in the order they
+// were recorded, with the path of the target prefixing their path. The requests that fail are skipped.
+//
+// Returns:
+//   - The errors of the store and of the requests that failed, joined with errors.Join.
+func ReplayRequests(store ReplayStore, target *url.URL) error {
+	requests, err := store.Replay(context.Background())
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for recorded := range requests {
+		if err := replayRequest(recorded, target); err != nil {
+			errs = append(errs, fmt.Errorf("supermuxer: replaying %s %s: %w", recorded.Method, recorded.URL, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func replayRequest(recorded RecordedRequest, target *url.URL) error {
+	recordedURL, err := url.ParseRequestURI(recorded.URL)
+	if err != nil {
+		return err
+	}
+
+	u := *target
+	u.Path = strings.TrimSuffix(u.Path, "/") + recordedURL.Path
+	u.RawPath = ""
+	u.RawQuery = recordedURL.RawQuery
+
+	req, err := http.NewRequest(recorded.Method, u.String(), bytes.NewReader(recorded.Body))
+	if err != nil {
+		return err
+	}
+	req.Header = recorded.Header.Clone()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
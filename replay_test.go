@@ -0,0 +1,120 @@
+package supermuxer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRequestReplayMiddleware(t *testing.T) {
+	store := NewInMemoryReplayStore()
+
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(NewRequestReplayMiddleware(store)).Post("/orders", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Header.Set("X-Handled", "true")
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write(append([]byte("created "), body...))
+	})
+
+	for _, body := range []string{`{"item":"book"}`, `{"item":"pen"}`} {
+		req := httptest.NewRequest(http.MethodPost, "/orders?source=web", strings.NewReader(body))
+		req.Header.Set("X-Tenant", "acme")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusCreated || rec.Body.String() != "created "+body {
+			t.Fatalf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusCreated, "created "+body)
+		}
+	}
+
+	t.Run("replay output", func(t *testing.T) {
+		requests, err := store.Replay(context.Background())
+		if err != nil {
+			t.Fatalf("Replay() error = %v", err)
+		}
+
+		var bodies []string
+		for recorded := range requests {
+			if recorded.Method != http.MethodPost || recorded.URL != "/orders?source=web" || recorded.StatusCode != http.StatusCreated {
+				t.Fatalf("recorded = %s %s %d, want %s %s %d", recorded.Method, recorded.URL, recorded.StatusCode,
+					http.MethodPost, "/orders?source=web", http.StatusCreated)
+			}
+
+			if recorded.Header.Get("X-Tenant") != "acme" || recorded.Header.Get("X-Handled") != "" {
+				t.Fatalf("recorded header = %v, want the header sent by the client", recorded.Header)
+			}
+
+			if string(recorded.ResponseBody) != "created "+string(recorded.Body) {
+				t.Fatalf("response body = %q, want %q", recorded.ResponseBody, "created "+string(recorded.Body))
+			}
+
+			if recorded.RecordedAt.IsZero() {
+				t.Fatal("RecordedAt is zero")
+			}
+
+			bodies = append(bodies, string(recorded.Body))
+		}
+
+		if want := []string{`{"item":"book"}`, `{"item":"pen"}`}; !slices.Equal(bodies, want) {
+			t.Fatalf("bodies = %q, want %q", bodies, want)
+		}
+	})
+
+	t.Run("cancelled replay", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		requests, _ := store.Replay(ctx)
+		for range requests {
+		}
+	})
+
+	t.Run("replay requests", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			received []string
+		)
+
+		staging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Tenant")+" "+string(body))
+		}))
+		defer staging.Close()
+
+		target, _ := url.Parse(staging.URL + "/staging/")
+		if err := ReplayRequests(store, target); err != nil {
+			t.Fatalf("ReplayRequests() error = %v", err)
+		}
+
+		want := []string{
+			`POST /staging/orders?source=web acme {"item":"book"}`,
+			`POST /staging/orders?source=web acme {"item":"pen"}`,
+		}
+
+		if !slices.Equal(received, want) {
+			t.Fatalf("received = %q, want %q", received, want)
+		}
+	})
+
+	t.Run("unreachable target", func(t *testing.T) {
+		staging := httptest.NewServer(http.NotFoundHandler())
+		target, _ := url.Parse(staging.URL)
+		staging.Close()
+
+		err := ReplayRequests(store, target)
+		if err == nil || !strings.Contains(err.Error(), "supermuxer: replaying POST /orders?source=web") {
+			t.Fatalf("ReplayRequests() error = %v, want the failed requests", err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package supermuxer
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDOptions configures the middleware created by NewRequestIDMiddleware.
+type RequestIDOptions struct {
+	// HeaderName is the request and response header holding the ID. Defaults to 'X-Request-ID'.
+	HeaderName string
+	// Generator creates the ID of requests that do not have one. Defaults to a random UUID v4.
+	Generator func() string
+}
+
+type requestIDContextKey struct{}
+
+// NewRequestIDMiddleware creates a middleware that gives every request an ID, reusing the one sent in the
+// header by upstream services or generating a new one. The ID is stored in the request context, readable with
+// RequestIDFromContext, and written to the response header.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewRequestIDMiddleware(supermuxer.RequestIDOptions{}))
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		with the 'X-Request-ID' header in the response
+func NewRequestIDMiddleware(opts RequestIDOptions) MiddlewareFunc {
+	if opts.HeaderName == "" {
+		opts.HeaderName = "X-Request-ID"
+	}
+
+	if opts.Generator == nil {
+		opts.Generator = newUUID
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(opts.HeaderName)
+			if id == "" {
+				id = opts.Generator()
+			}
+
+			w.Header().Set(opts.HeaderName, id)
+			next(w, r.WithContext(context.WithValue(r.Context(), requestIDContextKey{}, id)))
+		}
+	}
+}
+
+// RequestIDFromContext returns the request ID stored by the middleware created by NewRequestIDMiddleware,
+// or an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// newUUID generates a random UUID v4.
+func newUUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,58 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	echoID := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(RequestIDFromContext(r.Context())))
+	}
+
+	t.Run("generated", func(t *testing.T) {
+		handler := NewRequestIDMiddleware(RequestIDOptions{})(echoID)
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		id := rec.Header().Get("X-Request-ID")
+		if !uuid.MatchString(id) || rec.Body.String() != id {
+			t.Fatalf("header = %q, context = %q, want the same UUID v4", id, rec.Body.String())
+		}
+
+		other := httptest.NewRecorder()
+		handler(other, httptest.NewRequest(http.MethodGet, "/", nil))
+		if other.Header().Get("X-Request-ID") == id {
+			t.Fatal("two requests got the same ID")
+		}
+	})
+
+	t.Run("propagated", func(t *testing.T) {
+		handler := NewRequestIDMiddleware(RequestIDOptions{})(echoID)
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", "upstream-id")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		if rec.Header().Get("X-Request-ID") != "upstream-id" || rec.Body.String() != "upstream-id" {
+			t.Fatalf("header = %q, context = %q, want %q", rec.Header().Get("X-Request-ID"), rec.Body.String(), "upstream-id")
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		handler := NewRequestIDMiddleware(RequestIDOptions{HeaderName: "X-Correlation-ID", Generator: func() string { return "fixed" }})(echoID)
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Header().Get("X-Correlation-ID") != "fixed" || rec.Body.String() != "fixed" || rec.Header().Get("X-Request-ID") != "" {
+			t.Fatalf("headers = %v, context = %q, want only 'X-Correlation-ID: fixed'", rec.Header(), rec.Body.String())
+		}
+	})
+
+	if id := RequestIDFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); id != "" {
+		t.Fatalf("ID without the middleware = %q, want none", id)
+	}
+}
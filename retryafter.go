@@ -0,0 +1,38 @@
+package supermuxer
+
+import (
+	"net/http"
+	"time"
+)
+
+// NewRetryAfterMiddleware creates a middleware that asks retryFn whether every request must be retried later,
+// e.g. during a maintenance window or while a backend is unhealthy. When it returns true, the request is answered
+// with 503 and the 'Retry-After' header set to the duration rounded up to whole seconds, instead of calling the next
+// handler.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	maintenance := supermuxer.NewRetryAfterMiddleware(func(r *http.Request) (bool, time.Duration) {
+//		return inMaintenance.Load(), 5 * time.Minute
+//	})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(maintenance).Get("/orders", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /orders'
+//		responding with 503 and 'Retry-After: 300' during the maintenance
+func NewRetryAfterMiddleware(retryFn func(*http.Request) (bool, time.Duration)) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if retry, after := retryFn(r); retry {
+				w.Header().Set("Retry-After", retryAfterSeconds(after))
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
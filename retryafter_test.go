@@ -0,0 +1,64 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryAfterMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		retry      bool
+		after      time.Duration
+		status     int
+		retryAfter string
+	}{
+		{name: "retry later", retry: true, after: 2 * time.Minute, status: http.StatusServiceUnavailable, retryAfter: "120"},
+		{name: "rounded up", retry: true, after: 1500 * time.Millisecond, status: http.StatusServiceUnavailable, retryAfter: "2"},
+		{name: "at least one second", retry: true, status: http.StatusServiceUnavailable, retryAfter: "1"},
+		{name: "no retry", after: time.Minute, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := NewRetryAfterMiddleware(func(r *http.Request) (bool, time.Duration) {
+				return tt.retry, tt.after
+			})(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+			})
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+
+			if called == tt.retry {
+				t.Fatalf("handler called = %v, want %v", called, !tt.retry)
+			}
+		})
+	}
+
+	t.Run("decided per request", func(t *testing.T) {
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(NewRetryAfterMiddleware(func(r *http.Request) (bool, time.Duration) {
+			return r.URL.Path == "/reports", 30 * time.Second
+		})).Get("/{path}", textHandler("ok"))
+
+		if rec := serve(mux, http.MethodGet, "/reports"); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+
+		if rec := serve(mux, http.MethodGet, "/users"); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package supermuxer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+)
+
+// ReverseProxyConfig configures the middleware created by NewReverseProxyMiddlewareWithConfig.
+type ReverseProxyConfig struct {
+	// Target is the upstream URL. Its path is prepended to the path of the proxied requests. It is required.
+	Target *url.URL
+	// StripPrefix is removed from the path of the requests before they are proxied, e.g. the router base path.
+	StripPrefix string
+	// ModifyResponse changes the upstream responses before they are sent. An error responds with 502.
+	ModifyResponse func(*http.Response) error
+}
+
+// NewReverseProxyMiddleware creates a middleware that proxies every request to the target with
+// httputil.ReverseProxy, instead of calling the next handler. The 'X-Forwarded-For', 'X-Forwarded-Host' and
+// 'X-Forwarded-Proto' headers are set, keeping the addresses of the previous proxies.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//   - An error if the target is nil.
+//
+// Example:
+//
+//	target, _ := url.Parse("http://users-service:8080")
+//	proxy, err := supermuxer.NewReverseProxyMiddleware(target)
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(proxy).Any("/users/{path...}", nil)
+//
+//	# Result: supermuxer configuration to proxy the requests for the endpoint '/users/{path...}'
+//		to 'http://users-service:8080/users/{path...}'
+func NewReverseProxyMiddleware(target *url.URL) (MiddlewareFunc, error) {
+	return NewReverseProxyMiddlewareWithConfig(ReverseProxyConfig{Target: target})
+}
+
+// NewReverseProxyMiddlewareWithConfig works the same way as NewReverseProxyMiddleware, configured with the config.
+func NewReverseProxyMiddlewareWithConfig(cfg ReverseProxyConfig) (MiddlewareFunc, error) {
+	if cfg.Target == nil {
+		return nil, errors.New("supermuxer: reverse proxy target is required")
+	}
+
+	prefix := strings.TrimSuffix(cfg.StripPrefix, "/")
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			if prefix != "" {
+				pr.Out.URL.Path = strings.TrimPrefix(pr.Out.URL.Path, prefix)
+				pr.Out.URL.RawPath = ""
+
+				if !strings.HasPrefix(pr.Out.URL.Path, "/") {
+					pr.Out.URL.Path = "/" + pr.Out.URL.Path
+				}
+			}
+
+			pr.SetURL(cfg.Target)
+
+			// SetXForwarded appends the client address to the addresses of the previous proxies.
+			pr.Out.Header["X-Forwarded-For"] = pr.In.Header["X-Forwarded-For"]
+			pr.SetXForwarded()
+		},
+		ModifyResponse: cfg.ModifyResponse,
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			proxy.ServeHTTP(w, r)
+		}
+	}, nil
+}
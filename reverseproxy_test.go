@@ -0,0 +1,113 @@
+package supermuxer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestReverseProxyMiddleware(t *testing.T) {
+	var upstreamReq *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamReq = r
+		w.Header().Set("X-Upstream", "users-service")
+		w.Write([]byte("upstream " + r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	target, _ := url.Parse(upstream.URL + "/internal")
+
+	send := func(t *testing.T, mux *http.ServeMux, path string) *http.Response {
+		t.Helper()
+
+		server := httptest.NewServer(mux)
+		t.Cleanup(server.Close)
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		req.Header.Set("Authorization", "Bearer token")
+
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+
+		return resp
+	}
+
+	t.Run("path and headers", func(t *testing.T) {
+		proxy, err := NewReverseProxyMiddleware(target)
+		if err != nil {
+			t.Fatalf("NewReverseProxyMiddleware() error = %v", err)
+		}
+
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(proxy).Get("/users/{id}", textHandler("not proxied"))
+
+		resp := send(t, mux, "/users/7?fields=name")
+		body, _ := io.ReadAll(resp.Body)
+
+		if string(body) != "upstream /internal/users/7" || resp.Header.Get("X-Upstream") != "users-service" {
+			t.Fatalf("response = %q %v, want the upstream response", body, resp.Header)
+		}
+
+		if upstreamReq.URL.RawQuery != "fields=name" {
+			t.Fatalf("upstream query = %q, want %q", upstreamReq.URL.RawQuery, "fields=name")
+		}
+
+		if got := upstreamReq.Header.Get("Authorization"); got != "Bearer token" {
+			t.Fatalf("upstream Authorization = %q, want %q", got, "Bearer token")
+		}
+
+		if got := upstreamReq.Header.Get("X-Forwarded-For"); got != "198.51.100.1, 127.0.0.1" {
+			t.Fatalf("upstream X-Forwarded-For = %q, want %q", got, "198.51.100.1, 127.0.0.1")
+		}
+
+		if upstreamReq.Header.Get("X-Forwarded-Host") == "" || upstreamReq.Header.Get("X-Forwarded-Proto") != "http" {
+			t.Fatalf("upstream forwarded headers = %v, want X-Forwarded-Host and X-Forwarded-Proto", upstreamReq.Header)
+		}
+	})
+
+	t.Run("strip prefix", func(t *testing.T) {
+		proxy, _ := NewReverseProxyMiddlewareWithConfig(ReverseProxyConfig{Target: target, StripPrefix: "/api/"})
+
+		mux := http.NewServeMux()
+		New(mux).SubGroup("/api").AddMiddlewares(proxy).Get("/users", textHandler("not proxied"))
+
+		if body, _ := io.ReadAll(send(t, mux, "/api/users").Body); string(body) != "upstream /internal/users" {
+			t.Fatalf("body = %q, want %q", body, "upstream /internal/users")
+		}
+	})
+
+	t.Run("modify response", func(t *testing.T) {
+		proxy, _ := NewReverseProxyMiddlewareWithConfig(ReverseProxyConfig{Target: target, ModifyResponse: func(resp *http.Response) error {
+			if resp.Request.URL.Path == "/internal/forbidden" {
+				return errors.New("forbidden upstream path")
+			}
+
+			resp.Header.Del("X-Upstream")
+			return nil
+		}})
+
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(proxy).Get("/{path}", textHandler("not proxied"))
+
+		if resp := send(t, mux, "/users"); resp.Header.Get("X-Upstream") != "" {
+			t.Fatalf("X-Upstream = %q, want none", resp.Header.Get("X-Upstream"))
+		}
+
+		if resp := send(t, mux, "/forbidden"); resp.StatusCode != http.StatusBadGateway {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusBadGateway)
+		}
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		if _, err := NewReverseProxyMiddleware(nil); err == nil {
+			t.Fatal("NewReverseProxyMiddleware(nil) error = nil, want an error")
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package supermuxer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+)
+
+// SanitizerFunc cleans a request body for the middleware created by NewInputSanitizationMiddleware,
+// returning an error to reject it.
+type SanitizerFunc func([]byte) ([]byte, error)
+
+// NewInputSanitizationMiddleware creates a middleware that replaces the request body with the one cleaned by the
+// sanitizer before the next handler, updating its 'Content-Length'. Requests whose body cannot be read or is
+// rejected by the sanitizer get 400. The whole body is read before the sanitizer, so it should be limited with
+// http.MaxBytesReader or a middleware like NewBodySizeLimitMiddleware.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewInputSanitizationMiddleware(supermuxer.NewDefaultSanitizer(1 << 20))).Post("/comments", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /comments'
+//		without the control characters of the body, and responding with 400 to the bodies over 1 MiB
+func NewInputSanitizationMiddleware(sanitizer SanitizerFunc) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err == nil {
+				body, err = sanitizer(body)
+			}
+
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+			next(w, r)
+		}
+	}
+}
+
+// NewDefaultSanitizer creates a SanitizerFunc that removes the null bytes and the control characters below 0x20,
+// except tab, newline and carriage return, and rejects the bodies over maxBodyBytes. Bytes below 0x20 are never
+// part of a multi-byte UTF-8 character, so the valid UTF-8 text stays valid.
+func NewDefaultSanitizer(maxBodyBytes int64) SanitizerFunc {
+	return func(body []byte) ([]byte, error) {
+		if int64(len(body)) > maxBodyBytes {
+			return nil, fmt.Errorf("supermuxer: body of %d bytes exceeds the limit of %d bytes", len(body), maxBodyBytes)
+		}
+
+		return slices.DeleteFunc(body, func(b byte) bool {
+			return b < 0x20 && b != '\t' && b != '\n' && b != '\r'
+		}), nil
+	}
+}
@@ -0,0 +1,98 @@
+package supermuxer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestInputSanitizationMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		sanitizer  SanitizerFunc
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "control characters",
+			sanitizer:  NewDefaultSanitizer(1 << 10),
+			body:       "hel\x00lo\x07\x1b wor\x08ld",
+			wantStatus: http.StatusOK,
+			wantBody:   "hello world",
+		},
+		{
+			name:       "whitespace and UTF-8",
+			sanitizer:  NewDefaultSanitizer(1 << 10),
+			body:       "linha 1\r\n\tcoração\n",
+			wantStatus: http.StatusOK,
+			wantBody:   "linha 1\r\n\tcoração\n",
+		},
+		{
+			name:       "body at the limit",
+			sanitizer:  NewDefaultSanitizer(5),
+			body:       "12345",
+			wantStatus: http.StatusOK,
+			wantBody:   "12345",
+		},
+		{
+			name:       "body over the limit",
+			sanitizer:  NewDefaultSanitizer(5),
+			body:       "123456",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "custom sanitizer",
+			sanitizer:  func(body []byte) ([]byte, error) { return []byte(strings.ToUpper(string(body))), nil },
+			body:       "comment",
+			wantStatus: http.StatusOK,
+			wantBody:   "COMMENT",
+		},
+		{
+			name:       "rejected body",
+			sanitizer:  func([]byte) ([]byte, error) { return nil, errors.New("rejected") },
+			body:       "comment",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			handler := NewInputSanitizationMiddleware(tt.sanitizer)(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatalf("ReadAll error = %v", err)
+				}
+
+				want := strconv.Itoa(len(body))
+				if r.ContentLength != int64(len(body)) || r.Header.Get("Content-Length") != want {
+					t.Fatalf("Content-Length = %d (header %q), want %s", r.ContentLength, r.Header.Get("Content-Length"), want)
+				}
+
+				w.Write(body)
+			})
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodPost, "/comments", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("handler called = %v, want %v", called, tt.wantStatus == http.StatusOK)
+			}
+
+			if called && rec.Body.String() != tt.wantBody {
+				t.Fatalf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
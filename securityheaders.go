@@ -0,0 +1,66 @@
+package supermuxer
+
+import (
+	"net/http"
+)
+
+// SecurityHeadersConfig configures the middleware created by NewSecurityHeadersMiddleware.
+// Each field is the value of its header, and an empty string skips the header.
+type SecurityHeadersConfig struct {
+	StrictTransportSecurity string
+	XContentTypeOptions     string
+	XFrameOptions           string
+	ContentSecurityPolicy   string
+	ReferrerPolicy          string
+}
+
+// DefaultSecurityHeadersConfig returns a configuration with sane defaults for APIs.
+func DefaultSecurityHeadersConfig() SecurityHeadersConfig {
+	return SecurityHeadersConfig{
+		StrictTransportSecurity: "max-age=63072000; includeSubDomains",
+		XContentTypeOptions:     "nosniff",
+		XFrameOptions:           "DENY",
+		ContentSecurityPolicy:   "default-src 'none'; frame-ancestors 'none'",
+		ReferrerPolicy:          "no-referrer",
+	}
+}
+
+// NewSecurityHeadersMiddleware creates a middleware that sets the configured security headers on every response
+// before calling the next handler, so handlers can still override them.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewSecurityHeadersMiddleware(supermuxer.DefaultSecurityHeadersConfig()))
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users' with the security headers
+func NewSecurityHeadersMiddleware(cfg SecurityHeadersConfig) MiddlewareFunc {
+	headers := map[string]string{
+		"Strict-Transport-Security": cfg.StrictTransportSecurity,
+		"X-Content-Type-Options":    cfg.XContentTypeOptions,
+		"X-Frame-Options":           cfg.XFrameOptions,
+		"Content-Security-Policy":   cfg.ContentSecurityPolicy,
+		"Referrer-Policy":           cfg.ReferrerPolicy,
+	}
+
+	for name, value := range headers {
+		if value == "" {
+			delete(headers, name)
+		}
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			for name, value := range headers {
+				header.Set(name, value)
+			}
+
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package supermuxer
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	cfg := DefaultSecurityHeadersConfig()
+	cfg.ContentSecurityPolicy = ""
+
+	handler := NewSecurityHeadersMiddleware(cfg)(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Frame-Options", "SAMEORIGIN")
+	})
+	rec := serve(handler, http.MethodGet, "/")
+
+	want := map[string]string{
+		"Strict-Transport-Security": "max-age=63072000; includeSubDomains",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "SAMEORIGIN",
+		"Referrer-Policy":           "no-referrer",
+	}
+
+	for name, value := range want {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	if values := rec.Header().Values("Content-Security-Policy"); len(values) != 0 {
+		t.Errorf("Content-Security-Policy = %q, want no header", values)
+	}
+}
@@ -0,0 +1,234 @@
+package supermuxer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SessionConfig configures the middleware created by NewCookieSessionMiddleware.
+type SessionConfig struct {
+	// CookieName is the cookie holding the session. Defaults to 'session'.
+	CookieName string
+	// MaxAge is the lifetime of the session in seconds. Defaults to 86400, one day.
+	MaxAge int
+	// HTTPOnly hides the cookie from JavaScript.
+	HTTPOnly bool
+	// Secure only sends the cookie over HTTPS.
+	Secure bool
+	// SameSite is the SameSite attribute of the cookie. Defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+	// Secret signs the cookie with HMAC-SHA256, so it cannot be forged. It is required.
+	Secret []byte
+}
+
+// Session holds the values of the session of a request, stored in a signed cookie by the middleware created by
+// NewCookieSessionMiddleware. It is safe for concurrent use.
+type Session struct {
+	mu       sync.Mutex
+	values   map[string]string
+	modified bool
+}
+
+type sessionPayload struct {
+	Values    map[string]string `json:"v"`
+	ExpiresAt int64             `json:"e"`
+}
+
+type sessionContextKey struct{}
+
+// Get returns the value stored under the key, and whether there is one.
+func (s *Session) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores the value under the key.
+func (s *Session) Set(key string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	s.modified = true
+}
+
+// Delete removes the value stored under the key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	s.modified = true
+}
+
+// sessionWriter saves the session cookie right before the response headers are written.
+type sessionWriter struct {
+	http.ResponseWriter
+	save        func()
+	wroteHeader bool
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.save()
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sessionWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewCookieSessionMiddleware creates a middleware that keeps the session of every client in a cookie, signed with the
+// secret and expiring after MaxAge. The session is stored in the request context, readable with SessionFromContext,
+// and a missing, forged or expired cookie starts an empty session. Modified sessions are signed and saved again
+// when the response headers are written, so the changes made after writing the response are lost.
+// The values must fit in a cookie, which browsers limit to about 4 KB. It panics if the secret is empty.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	sessions := supermuxer.NewCookieSessionMiddleware(supermuxer.SessionConfig{Secret: secret, HTTPOnly: true, Secure: true})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(sessions).Post("/cart", func(w http.ResponseWriter, r *http.Request) {
+//		session, _ := supermuxer.SessionFromContext(r.Context())
+//		session.Set("cart", r.FormValue("item"))
+//	})
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /cart'
+//		storing the item in the session cookie
+func NewCookieSessionMiddleware(cfg SessionConfig) MiddlewareFunc {
+	if len(cfg.Secret) == 0 {
+		panic("supermuxer: session secret is required")
+	}
+
+	if cfg.CookieName == "" {
+		cfg.CookieName = "session"
+	}
+
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = 86400
+	}
+
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			session := &Session{values: map[string]string{}}
+			if cookie, err := r.Cookie(cfg.CookieName); err == nil {
+				if values, ok := decodeSession(cookie.Value, cfg.Secret); ok {
+					session.values = values
+				}
+			}
+
+			save := func() {
+				session.mu.Lock()
+				defer session.mu.Unlock()
+
+				if !session.modified {
+					return
+				}
+
+				http.SetCookie(w, &http.Cookie{
+					Name:     cfg.CookieName,
+					Value:    encodeSession(session.values, cfg.MaxAge, cfg.Secret),
+					Path:     "/",
+					MaxAge:   cfg.MaxAge,
+					HttpOnly: cfg.HTTPOnly,
+					Secure:   cfg.Secure,
+					SameSite: cfg.SameSite,
+				})
+				session.modified = false
+			}
+
+			sw := &sessionWriter{ResponseWriter: w, save: save}
+			next(sw, r.WithContext(context.WithValue(r.Context(), sessionContextKey{}, session)))
+
+			if !sw.wroteHeader {
+				save()
+			}
+		}
+	}
+}
+
+// SessionFromContext returns the session stored by the middleware created by NewCookieSessionMiddleware.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	session, ok := ctx.Value(sessionContextKey{}).(*Session)
+	return session, ok
+}
+
+// encodeSession creates the cookie value made of the encoded payload and its signature.
+func encodeSession(values map[string]string, maxAge int, secret []byte) string {
+	payload, _ := json.Marshal(sessionPayload{
+		Values:    values,
+		ExpiresAt: time.Now().Add(time.Duration(maxAge) * time.Second).Unix(),
+	})
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signSessionPayload(encoded, secret)
+}
+
+func signSessionPayload(payload string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// decodeSession returns the values of the cookie when it was signed with the secret and has not expired.
+func decodeSession(value string, secret []byte) (map[string]string, bool) {
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signSessionPayload(encoded, secret))) {
+		return nil, false
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+
+	var payload sessionPayload
+	if err := json.Unmarshal(decoded, &payload); err != nil || time.Now().Unix() >= payload.ExpiresAt {
+		return nil, false
+	}
+
+	if payload.Values == nil {
+		payload.Values = map[string]string{}
+	}
+
+	return payload.Values, true
+}
@@ -0,0 +1,115 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCookieSessionMiddleware(t *testing.T) {
+	secret := []byte("session-secret")
+
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(NewCookieSessionMiddleware(SessionConfig{Secret: secret, HTTPOnly: true, Secure: true, MaxAge: 3600}))
+	superRouter.Post("/login", func(w http.ResponseWriter, r *http.Request) {
+		session, _ := SessionFromContext(r.Context())
+		session.Set("user", r.URL.Query().Get("user"))
+		session.Set("theme", "dark")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	superRouter.Get("/me", func(w http.ResponseWriter, r *http.Request) {
+		session, _ := SessionFromContext(r.Context())
+		user, _ := session.Get("user")
+		theme, _ := session.Get("theme")
+		w.Write([]byte(user + " " + theme))
+	})
+	superRouter.Post("/theme/reset", func(w http.ResponseWriter, r *http.Request) {
+		session, _ := SessionFromContext(r.Context())
+		session.Delete("theme")
+	})
+
+	send := func(method, target string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	sessionCookie := func(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+		t.Helper()
+
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == "session" {
+				return cookie
+			}
+		}
+
+		t.Fatalf("Set-Cookie = %q, want the session cookie", rec.Header().Values("Set-Cookie"))
+		return nil
+	}
+
+	login := send(http.MethodPost, "/login?user=alice", nil)
+	cookie := sessionCookie(t, login)
+
+	t.Run("creation", func(t *testing.T) {
+		if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteLaxMode || cookie.MaxAge != 3600 || cookie.Path != "/" {
+			t.Fatalf("cookie = %+v, want the configured attributes", cookie)
+		}
+
+		if strings.Contains(cookie.Value, "alice") {
+			t.Fatalf("cookie value = %q, want the values encoded", cookie.Value)
+		}
+	})
+
+	t.Run("read", func(t *testing.T) {
+		rec := send(http.MethodGet, "/me", cookie)
+		if rec.Body.String() != "alice dark" {
+			t.Fatalf("body = %q, want %q", rec.Body.String(), "alice dark")
+		}
+
+		if rec.Header().Get("Set-Cookie") != "" {
+			t.Fatalf("Set-Cookie = %q, want none for an unmodified session", rec.Header().Get("Set-Cookie"))
+		}
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+		updated := sessionCookie(t, send(http.MethodPost, "/theme/reset", cookie))
+
+		if rec := send(http.MethodGet, "/me", updated); rec.Body.String() != "alice " {
+			t.Fatalf("body = %q, want %q", rec.Body.String(), "alice ")
+		}
+	})
+
+	t.Run("forged cookie", func(t *testing.T) {
+		encoded, signature, _ := strings.Cut(cookie.Value, ".")
+		forged := encoded[:len(encoded)-2] + "xx." + signature
+
+		if rec := send(http.MethodGet, "/me", &http.Cookie{Name: "session", Value: forged}); rec.Body.String() != " " {
+			t.Fatalf("body = %q, want an empty session", rec.Body.String())
+		}
+
+		other := encodeSession(map[string]string{"user": "mallory"}, 3600, []byte("other-secret"))
+		if rec := send(http.MethodGet, "/me", &http.Cookie{Name: "session", Value: other}); rec.Body.String() != " " {
+			t.Fatalf("body = %q, want an empty session", rec.Body.String())
+		}
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		expired := encodeSession(map[string]string{"user": "alice"}, -1, secret)
+
+		if rec := send(http.MethodGet, "/me", &http.Cookie{Name: "session", Value: expired}); rec.Body.String() != " " {
+			t.Fatalf("body = %q, want an empty session", rec.Body.String())
+		}
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		assertPanics(t, "session secret is required", func() {
+			NewCookieSessionMiddleware(SessionConfig{})
+		})
+	})
+}
@@ -0,0 +1,77 @@
+package supermuxer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// discardWriter is the http.ResponseWriter of the shadow requests, whose responses are ignored.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *discardWriter) WriteHeader(int) {}
+
+// NewShadowTrafficMiddleware creates a middleware that sends a copy of every request to the shadow handler in the
+// background, e.g. a new implementation of the service being dark launched, while the next handler serves the
+// response as usual. The shadow request has its own copy of the body and a context that is not canceled with the
+// request. Its response is discarded, and its panics are recovered and logged with the default slog logger.
+// Requests whose body cannot be read get 400.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewShadowTrafficMiddleware(newOrdersService)).Post("/orders", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /orders' with handler,
+//		while newOrdersService gets a copy of every request
+func NewShadowTrafficMiddleware(shadow http.Handler) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			var body []byte
+
+			if r.Body != nil && r.Body != http.NoBody {
+				var err error
+				body, err = io.ReadAll(r.Body)
+				r.Body.Close()
+				if err != nil {
+					http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+					return
+				}
+
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			shadowRequest := r.Clone(context.WithoutCancel(r.Context()))
+			if body != nil {
+				shadowRequest.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			go func() {
+				defer func() {
+					if v := recover(); v != nil {
+						slog.Error("supermuxer: shadow handler panicked", "panic", v)
+					}
+				}()
+
+				shadow.ServeHTTP(&discardWriter{header: http.Header{}}, shadowRequest)
+			}()
+
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package supermuxer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type shadowRequest struct {
+	method string
+	path   string
+	body   string
+	ctxErr error
+}
+
+func TestShadowTrafficMiddleware(t *testing.T) {
+	shadowed := make(chan shadowRequest, 4)
+	release := make(chan struct{})
+
+	shadow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("shadow response"))
+		shadowed <- shadowRequest{method: r.Method, path: r.URL.Path, body: string(body), ctxErr: r.Context().Err()}
+	})
+
+	handler := NewShadowTrafficMiddleware(shadow)(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("primary " + string(body)))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequestWithContext(ctx, http.MethodPost, "/orders", strings.NewReader("order 1")))
+
+	// The primary response does not wait for the shadow handler.
+	if rec.Code != http.StatusCreated || rec.Body.String() != "primary order 1" {
+		t.Fatalf("primary response = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusCreated, "primary order 1")
+	}
+
+	cancel()
+	close(release)
+
+	select {
+	case got := <-shadowed:
+		want := shadowRequest{method: http.MethodPost, path: "/orders", body: "order 1"}
+		if got != want {
+			t.Fatalf("shadow request = %+v, want %+v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the shadow handler was not called")
+	}
+
+	select {
+	case got := <-shadowed:
+		t.Fatalf("shadow handler called again with %+v", got)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestShadowTrafficMiddlewarePanic(t *testing.T) {
+	done := make(chan struct{})
+	shadow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		panic("shadow failure")
+	})
+
+	rec := httptest.NewRecorder()
+	NewShadowTrafficMiddleware(shadow)(textHandler("primary"))(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the shadow handler was not called")
+	}
+
+	if rec.Body.String() != "primary" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "primary")
+	}
+}
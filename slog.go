@@ -0,0 +1,58 @@
+package supermuxer
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// NewSlogMiddleware creates a middleware that logs every request with the logger once the handler returns,
+// with the 'method', 'path', 'status', 'duration_ms' and 'request_id' attributes.
+// Responses with 4xx are logged as warnings, 5xx as errors and the others as info. A nil logger uses slog.Default.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewRequestIDMiddleware(supermuxer.RequestIDOptions{}), supermuxer.NewSlogMiddleware(logger))
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users' logging it with its request ID
+func NewSlogMiddleware(logger *slog.Logger) MiddlewareFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := newStatusWriter(w)
+
+			next(sw, r)
+
+			status := sw.Status()
+			level := slog.LevelInfo
+
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			logger.LogAttrs(r.Context(), level, "http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Float64("duration_ms", durationMs(time.Since(start))),
+				slog.String("request_id", RequestIDFromContext(r.Context())),
+			)
+		}
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,74 @@
+package supermuxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+)
+
+func TestSlogMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		level  string
+	}{
+		{name: "success", status: http.StatusCreated, level: "INFO"},
+		{name: "client error", status: http.StatusNotFound, level: "WARN"},
+		{name: "server error", status: http.StatusBadGateway, level: "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+			mux := http.NewServeMux()
+			New(mux).AddMiddlewares(NewRequestIDMiddleware(RequestIDOptions{Generator: func() string { return "request-1" }}), NewSlogMiddleware(logger)).
+				Post("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(tt.status)
+				})
+			serve(mux, http.MethodPost, "/users/42?verbose=true")
+
+			var record map[string]any
+			if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+				t.Fatalf("log record %q: %v", logs.String(), err)
+			}
+
+			want := map[string]any{
+				"level":      tt.level,
+				"msg":        "http request",
+				"method":     http.MethodPost,
+				"path":       "/users/42",
+				"status":     float64(tt.status),
+				"request_id": "request-1",
+			}
+
+			for key, value := range want {
+				if record[key] != value {
+					t.Errorf("%s = %v, want %v", key, record[key], value)
+				}
+			}
+
+			if duration, ok := record["duration_ms"].(float64); !ok || duration < 0 {
+				t.Errorf("duration_ms = %v, want a non-negative number", record["duration_ms"])
+			}
+		})
+	}
+
+	t.Run("implicit status", func(t *testing.T) {
+		var logs bytes.Buffer
+		handler := NewSlogMiddleware(slog.New(slog.NewJSONHandler(&logs, nil)))(textHandler("users"))
+		serve(handler, http.MethodGet, "/users")
+
+		var record map[string]any
+		if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+			t.Fatalf("log record %q: %v", logs.String(), err)
+		}
+
+		if record["status"] != float64(http.StatusOK) || record["request_id"] != "" {
+			t.Fatalf("status = %v, request_id = %v, want 200 and an empty request ID", record["status"], record["request_id"])
+		}
+	})
+}
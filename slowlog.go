@@ -0,0 +1,50 @@
+package supermuxer
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// NewDurationLoggingMiddleware creates a middleware that logs the requests whose handler takes longer than the
+// threshold as warnings with the logger, with the 'method', 'path', 'duration_ms', 'status' and 'request_id'
+// attributes. The faster requests are not logged, so it can run alongside the middleware created by NewSlogMiddleware.
+// A nil logger uses slog.Default.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewDurationLoggingMiddleware(500*time.Millisecond, logger)).Get("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /reports'
+//		logging the requests that take longer than 500ms
+func NewDurationLoggingMiddleware(threshold time.Duration, logger *slog.Logger) MiddlewareFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := newStatusWriter(w)
+
+			next(sw, r)
+
+			duration := time.Since(start)
+			if duration <= threshold {
+				return
+			}
+
+			logger.WarnContext(r.Context(), "slow http request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Float64("duration_ms", durationMs(duration)),
+				slog.Int("status", sw.Status()),
+				slog.String("request_id", RequestIDFromContext(r.Context())),
+			)
+		}
+	}
+}
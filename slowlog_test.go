@@ -0,0 +1,69 @@
+package supermuxer
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDurationLoggingMiddleware(t *testing.T) {
+	const threshold = 20 * time.Millisecond
+
+	newRouter := func(logs *bytes.Buffer, delay time.Duration) http.Handler {
+		mux := http.NewServeMux()
+		New(mux).AddMiddlewares(
+			NewRequestIDMiddleware(RequestIDOptions{Generator: func() string { return "request-1" }}),
+			NewDurationLoggingMiddleware(threshold, slog.New(slog.NewJSONHandler(logs, nil))),
+		).Get("/reports/{id}", func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(delay)
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		return mux
+	}
+
+	t.Run("slow handler", func(t *testing.T) {
+		var logs bytes.Buffer
+		serve(newRouter(&logs, 2*threshold), http.MethodGet, "/reports/7?format=csv")
+
+		var record map[string]any
+		if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+			t.Fatalf("log record %q: %v", logs.String(), err)
+		}
+
+		want := map[string]any{
+			"level":      "WARN",
+			"msg":        "slow http request",
+			"method":     http.MethodGet,
+			"path":       "/reports/7",
+			"status":     float64(http.StatusAccepted),
+			"request_id": "request-1",
+		}
+
+		for key, value := range want {
+			if record[key] != value {
+				t.Errorf("%s = %v, want %v", key, record[key], value)
+			}
+		}
+
+		if duration, ok := record["duration_ms"].(float64); !ok || duration < float64(2*threshold/time.Millisecond) {
+			t.Errorf("duration_ms = %v, want at least %d", record["duration_ms"], 2*threshold/time.Millisecond)
+		}
+	})
+
+	t.Run("fast handler", func(t *testing.T) {
+		var logs bytes.Buffer
+		rec := serve(newRouter(&logs, 0), http.MethodGet, "/reports/7")
+
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusAccepted)
+		}
+
+		if logs.Len() != 0 {
+			t.Fatalf("log = %q, want no record", logs.String())
+		}
+	})
+}
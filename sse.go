@@ -0,0 +1,43 @@
+package supermuxer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// sseEventReplacer removes the line breaks of the event names, which would end the 'event' field.
+var sseEventReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+// sseDataReplacer normalizes the line breaks of the data, as '\r' and '\r\n' also end a field.
+var sseDataReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+func (r *router) NewSSEHandler(path string, producer func(ctx context.Context, send func(event, data string))) *router {
+	return r.Get(path, func(w http.ResponseWriter, req *http.Request) {
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+
+		controller := http.NewResponseController(w)
+		w.WriteHeader(http.StatusOK)
+		controller.Flush()
+
+		send := func(event, data string) {
+			if event = sseEventReplacer.Replace(event); event != "" {
+				fmt.Fprintf(w, "event: %s\n", event)
+			}
+
+			// Every line of the data needs its own field, as a newline ends the field.
+			for _, line := range strings.Split(sseDataReplacer.Replace(data), "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+
+			fmt.Fprint(w, "\n")
+			controller.Flush()
+		}
+
+		producer(req.Context(), send)
+	})
+}
@@ -0,0 +1,81 @@
+package supermuxer
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewSSEHandler(t *testing.T) {
+	t.Run("event stream format", func(t *testing.T) {
+		mux := http.NewServeMux()
+		New(mux).NewSSEHandler("/events", func(ctx context.Context, send func(event, data string)) {
+			send("", "hello")
+			send("update", `{"id":1}`)
+			send("multi", "line 1\nline 2\r\nline 3\rline 4")
+			send("inject\r\ndata: forged", "ok")
+		})
+
+		rec := serve(mux, http.MethodGet, "/events")
+
+		header := rec.Header()
+		if header.Get("Content-Type") != "text/event-stream" || header.Get("Cache-Control") != "no-cache" || header.Get("Connection") != "keep-alive" {
+			t.Fatalf("headers = %v, want the event stream headers", header)
+		}
+
+		if !rec.Flushed {
+			t.Fatal("the stream was not flushed")
+		}
+
+		want := "data: hello\n\n" +
+			"event: update\ndata: {\"id\":1}\n\n" +
+			"event: multi\ndata: line 1\ndata: line 2\ndata: line 3\ndata: line 4\n\n" +
+			"event: injectdata: forged\ndata: ok\n\n"
+		if rec.Body.String() != want {
+			t.Fatalf("body = %q, want %q", rec.Body.String(), want)
+		}
+	})
+
+	t.Run("events are flushed as they are sent", func(t *testing.T) {
+		next := make(chan struct{})
+		mux := http.NewServeMux()
+		New(mux).NewSSEHandler("/events", func(ctx context.Context, send func(event, data string)) {
+			for range 2 {
+				<-next
+				send("tick", "now")
+			}
+			<-ctx.Done()
+		})
+
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		resp, err := server.Client().Get(server.URL + "/events")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		reader := bufio.NewReader(resp.Body)
+		for i := range 2 {
+			next <- struct{}{}
+
+			var event string
+			for range 3 {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					t.Fatalf("event %d: %v", i+1, err)
+				}
+				event += line
+			}
+
+			if event != "event: tick\ndata: now\n\n" {
+				t.Fatalf("event %d = %q, want %q", i+1, event, "event: tick\ndata: now\n\n")
+			}
+		}
+
+		// Closing the connection cancels the context of the producer.
+		resp.Body.Close()
+	})
+}
@@ -0,0 +1,55 @@
+package supermuxer
+
+import (
+	"net/http"
+)
+
+// flushWriter flushes the response to the client after every write.
+type flushWriter struct {
+	http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (w *flushWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.flusher.Flush()
+
+	return n, err
+}
+
+func (w *flushWriter) Flush() {
+	w.flusher.Flush()
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *flushWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewStreamingResponseMiddleware creates a middleware that flushes the response to the client after every write of
+// the next handlers, so chunked responses are sent incrementally. The writer still implements http.Flusher.
+// Responses whose writer cannot flush are not changed.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewStreamingResponseMiddleware()).Get("/exports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /exports'
+//		sending every chunk written by the handler as soon as it is written
+func NewStreamingResponseMiddleware() MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			flusher, ok := w.(http.Flusher)
+			if !ok {
+				next(w, r)
+				return
+			}
+
+			next(&flushWriter{ResponseWriter: w, flusher: flusher}, r)
+		}
+	}
+}
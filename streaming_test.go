@@ -0,0 +1,75 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// flushRecorder records the body written at every flush.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.Body.String())
+	r.ResponseRecorder.Flush()
+}
+
+// plainResponseWriter is an http.ResponseWriter that cannot flush.
+type plainResponseWriter struct {
+	http.ResponseWriter
+}
+
+func TestStreamingResponseMiddleware(t *testing.T) {
+	chunks := func(w http.ResponseWriter, r *http.Request) {
+		for _, chunk := range []string{"row 1\n", "row 2\n", "row 3\n"} {
+			w.Write([]byte(chunk))
+		}
+	}
+
+	t.Run("flushes every write", func(t *testing.T) {
+		rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		NewStreamingResponseMiddleware()(chunks)(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+		want := []string{"row 1\n", "row 1\nrow 2\n", "row 1\nrow 2\nrow 3\n"}
+		if !slices.Equal(rec.flushes, want) {
+			t.Fatalf("flushes = %q, want %q", rec.flushes, want)
+		}
+	})
+
+	t.Run("writer still flushes", func(t *testing.T) {
+		rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+		NewStreamingResponseMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+		})(rec, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+		if len(rec.flushes) != 1 || rec.Code != http.StatusAccepted {
+			t.Fatalf("flushes = %d, status = %d, want 1 and %d", len(rec.flushes), rec.Code, http.StatusAccepted)
+		}
+	})
+
+	t.Run("writer without flush", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		var got http.ResponseWriter
+		w := plainResponseWriter{rec}
+
+		NewStreamingResponseMiddleware()(func(w http.ResponseWriter, r *http.Request) {
+			got = w
+			chunks(w, r)
+		})(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+
+		if got != w || rec.Flushed {
+			t.Fatal("the writer without flush was changed")
+		}
+
+		if rec.Body.String() != "row 1\nrow 2\nrow 3\n" {
+			t.Fatalf("body = %q, want the three rows", rec.Body.String())
+		}
+	})
+}
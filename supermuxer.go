@@ -1,29 +1,535 @@
 package supermuxer
 
 import (
+	"context"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	neturl "net/url"
 	"slices"
+	"strings"
 )
 
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
 type (
 	MiddlewareFunc func(next http.HandlerFunc) http.HandlerFunc
 
+	// RouterOptions configures the router created by NewWithOptions.
+	RouterOptions struct {
+		// AutoHEAD registers a 'HEAD' route for every route registered with Get. See Router.WithAutoHEAD.
+		AutoHEAD bool
+		// TrailingSlashRedirect redirects the paths that only differ from a route by the trailing slash.
+		// See Router.WithTrailingSlashRedirect.
+		TrailingSlashRedirect bool
+		// NotFoundHandler handles the requests that match no route. See Router.NotFound.
+		NotFoundHandler http.HandlerFunc
+		// MethodNotAllowedHandler handles the requests that match a route but not its method. See Router.MethodNotAllowed.
+		MethodNotAllowedHandler http.HandlerFunc
+		// PanicHandler recovers from the panics of every route, including those of groups and subgroups,
+		// as the outermost middleware. See PanicRecovery.
+		PanicHandler func(http.ResponseWriter, *http.Request, any)
+		// GlobalMiddlewares wrap every route, including those of groups and subgroups, before the middlewares
+		// of the route. See Router.AddGlobalMiddlewares.
+		GlobalMiddlewares []MiddlewareFunc
+	}
+
+	// RouteInfo describes a route registered through the router.
+	RouteInfo struct {
+		// Method is the HTTP method of the route, e.g. 'GET'.
+		Method string
+		// Pattern is the path of the route including the router base path, e.g. '/users/{id}'.
+		Pattern string
+		// FullPattern is the pattern registered on the http.ServeMux, e.g. 'GET /users/{id}'.
+		FullPattern string
+		// Host is the host the route is restricted to with WithHost, if any.
+		Host string
+	}
+
+	// route keeps what is needed to register a route again, e.g. when it is mounted on another router.
+	route struct {
+		RouteInfo
+		handler     http.HandlerFunc
+		middlewares []MiddlewareFunc
+	}
+
+	// fallback handles the requests that match none of the routes registered on the http.ServeMux.
+	fallback struct {
+		mux                   *http.ServeMux
+		registered            bool
+		notFound              http.HandlerFunc
+		methodNotAllowed      http.HandlerFunc
+		trailingSlashRedirect bool
+	}
+
 	router struct {
 		mux         *http.ServeMux
 		basePath    string
 		middlewares []MiddlewareFunc
+		routes      *[]route
+		names       map[string]string
+		fallback    *fallback
+		autoHEAD    bool
+		recovery    MiddlewareFunc
+		host        string
+
+		// globalMiddlewares is shared by the router and all its copies, so it reaches every group and subgroup.
+		globalMiddlewares *[]MiddlewareFunc
+
+		// registeredPatterns detects duplicated routes before the http.ServeMux panics with a less clear message.
+		registeredPatterns map[string]struct{}
 	}
 
 	Router interface {
+		// Handler serves the requests with the underlying http.ServeMux, so the router can be passed
+		// directly to http.ListenAndServe or http.Server.
+		http.Handler
+
 		Get(string, http.HandlerFunc) *router
 		Post(path string, handler http.HandlerFunc) *router
 		Put(path string, handler http.HandlerFunc) *router
 		Delete(path string, handler http.HandlerFunc) *router
 		Patch(path string, handler http.HandlerFunc) *router
+		Head(path string, handler http.HandlerFunc) *router
+		Options(path string, handler http.HandlerFunc) *router
+		Trace(path string, handler http.HandlerFunc) *router
+		Connect(path string, handler http.HandlerFunc) *router
+
+		// Any registers the handler for every standard HTTP method of the path,
+		// except CONNECT. Each method is registered separately and wrapped in the current middlewares.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.Any("/echo", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoints 'GET /echo', 'HEAD /echo',
+		//		'POST /echo', 'PUT /echo', 'PATCH /echo', 'DELETE /echo', 'OPTIONS /echo' and 'TRACE /echo'
+		Any(path string, handler http.HandlerFunc) *router
+
+		// Handle registers an http.Handler, such as http.FileServer or http.StripPrefix, for the method and path.
+		// The handler is wrapped in the current middlewares like any http.HandlerFunc.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.Handle(http.MethodGet, "/assets/", http.StripPrefix("/assets/", fileServer))
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /assets/'
+		Handle(method string, path string, handler http.Handler) *router
+		HandleGet(path string, handler http.Handler) *router
+		HandlePost(path string, handler http.Handler) *router
+		HandlePut(path string, handler http.Handler) *router
+		HandlePatch(path string, handler http.Handler) *router
+		HandleDelete(path string, handler http.Handler) *router
+		HandleOptions(path string, handler http.Handler) *router
+
+		// Redirect redirects the requests of every method, like Any, from the path to the URL with the status code.
+		// It panics if the code is not 301, 302, 307 or 308.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.Redirect("/v1/users", "/v2/users", http.StatusPermanentRedirect)
+		//
+		//	# Result: supermuxer configuration to redirect the requests for the endpoint '/v1/users' to '/v2/users' with 308
+		Redirect(from string, to string, code int) *router
+
+		// StaticFiles serves the files of the local directory under the prefix with http.FileServer.
+		// The files are wrapped in the current middlewares like any other route.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.StaticFiles("/assets", "./public")
+		//
+		//	# Result: supermuxer configuration to handle the request 'GET /assets/css/app.css' with the file './public/css/app.css'
+		StaticFiles(prefix string, dir string) *router
+
+		// HealthCheck registers a 'GET' handler for the path that runs the checks concurrently, with a 5 seconds
+		// timeout, like the liveness and readiness probes of Kubernetes. It responds with the
+		// '{"status": "...", "checks": {...}}' JSON body, where each check, by its index, is "ok" or its error:
+		//   - 200 and "ok" when every check passes.
+		//   - 207 and "degraded" when some checks fail.
+		//   - 503 and "unhealthy" when every check fails.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.HealthCheck("/healthz").HealthCheck("/readyz", pingDatabase, pingCache)
+		//
+		//	# Result: supermuxer configuration to handle the requests for the endpoints 'GET /healthz' and 'GET /readyz',
+		//		the last one responding with 503 when both the database and the cache are down
+		HealthCheck(path string, checks ...HealthCheckFunc) *router
+
+		// ServeUpload registers a 'POST' handler for the path that parses the multipart form of the request and
+		// validates its files with the config, before calling the handler with them. Requests that are not
+		// multipart forms get 415. When both MaxFiles and MaxFileSize are set, bodies bigger than MaxFiles files of
+		// MaxFileSize, plus 1 MiB for the other parts, get 413 without being read any further.
+		// The temporary files of the form are removed once the handler returns.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.ServeUpload("/avatars", supermuxer.UploadConfig{MaxFiles: 1, AllowedMIMETypes: []string{"image/png"}}, handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /avatars'
+		//		calling handler with a single PNG file, and responding with 415 for other types
+		ServeUpload(path string, cfg UploadConfig, handler func(http.ResponseWriter, *http.Request, []*multipart.FileHeader)) *router
+
+		// ServeDownload registers a 'GET' handler for the path that serves the file as an attachment, with the
+		// 'Content-Disposition: attachment; filename=<name>' header, quoted or encoded as needed by mime.FormatMediaType,
+		// through http.ServeContent so range requests are supported. An empty content type is detected from the file extension or content.
+		// It responds with 404 when the file does not exist, and 500 when it cannot be opened.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.ServeDownload("/reports/latest", "./reports/2024.csv", "text/csv")
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /reports/latest'
+		//		with the '2024.csv' file as an attachment
+		ServeDownload(path string, filePath string, contentType string) *router
+
+		// NewSSEHandler registers a 'GET' handler for the path that streams Server-Sent Events, calling the producer
+		// with the request context and a send function. Each call to send writes an 'event: <event>' line, skipped
+		// for an empty event, and a 'data: <line>' line for every line of the data, then flushes the event.
+		// Line breaks are removed from the event, so it cannot start other fields, and '\r\n' or '\r' split the data
+		// like '\n'.
+		// The producer must return when the context is done, which happens when the client disconnects.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.NewSSEHandler("/events", func(ctx context.Context, send func(event, data string)) {
+		//		for {
+		//			select {
+		//			case <-ctx.Done():
+		//				return
+		//			case order := <-orders:
+		//				send("order", order)
+		//			}
+		//		}
+		//	})
+		//
+		//	# Result: supermuxer configuration to stream the orders as events for the endpoint 'GET /events'
+		NewSSEHandler(path string, producer func(ctx context.Context, send func(event, data string))) *router
+
+		// RouteNamed registers the handler for the method and path, like Get or Post,
+		// and stores the path template under the name so it can be resolved with URL.
+		// Names are shared by the router, its groups and its subgroups, and must be unique.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.SubGroup("/users").RouteNamed(http.MethodGet, "user", "/{id}", handler)
+		//	url, err := superRouter.URL("user", "42")
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users/{id}'
+		//		and url equal to '/users/42'
+		RouteNamed(method string, name string, path string, handler http.HandlerFunc) *router
+		GetNamed(name string, path string, handler http.HandlerFunc) *router
+		PostNamed(name string, path string, handler http.HandlerFunc) *router
+		PutNamed(name string, path string, handler http.HandlerFunc) *router
+		PatchNamed(name string, path string, handler http.HandlerFunc) *router
+		DeleteNamed(name string, path string, handler http.HandlerFunc) *router
+
+		// URL builds the path of a named route, replacing its '{param}' placeholders in order with the params.
+		//
+		// Returns:
+		//   - The path of the route with the params escaped in place of the placeholders.
+		//   - An error if the name is unknown or the number of params does not match the number of placeholders.
+		URL(name string, params ...string) (string, error)
+
+		// NotFound sets the handler for the requests that match none of the registered routes,
+		// replacing the plain text 404 of the http.ServeMux. The handler is wrapped like a route, in the global
+		// middlewares, the current middlewares and the PanicHandler of RouterOptions.
+		// The handler is shared by the router, its groups and its subgroups, so the last call wins.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1).NotFound(notFoundHandler)
+		//
+		//	# Result: supermuxer configuration to handle the requests for unknown endpoints with notFoundHandler
+		//		wrapped in middleware1
+		NotFound(handler http.HandlerFunc) *router
+
+		// MethodNotAllowed sets the handler for the requests whose path matches a registered route
+		// but whose method does not. The 'Allow' header is set with the methods registered for the path
+		// before the handler is called. Catch-all routes, like 'GET /' or 'GET /{path...}', do not count, as they
+		// match every path. The handler is wrapped like a route, in the global middlewares, the current middlewares
+		// and the PanicHandler of RouterOptions.
+		// The handler is shared by the router, its groups and its subgroups, so the last call wins.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.Get("/users", handler).MethodNotAllowed(methodNotAllowedHandler)
+		//
+		//	# Result: supermuxer configuration to handle the request 'POST /users' with methodNotAllowedHandler
+		MethodNotAllowed(handler http.HandlerFunc) *router
+
+		// WithTrailingSlashRedirect enables or disables redirecting the requests that match no route
+		// to the same path with or without the trailing slash, when a route for the request method matches it.
+		// GET and HEAD requests are redirected with 301 and the other methods with 308, so the method and body are kept.
+		// The http.ServeMux itself already redirects a path without the trailing slash to a route registered with it.
+		// The option is shared by the router, its groups and its subgroups, and is disabled by default.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.WithTrailingSlashRedirect(true).Get("/users", handler)
+		//
+		//	# Result: supermuxer configuration to redirect the request 'GET /users/' to '/users'
+		WithTrailingSlashRedirect(redirect bool) *router
+
+		// WithAutoHEAD enables or disables registering a 'HEAD' route, with the same handler, for every route
+		// registered with Get. The body is discarded by the server, after the middlewares, so the 'HEAD' responses
+		// get the same headers as the 'GET' ones, including 'Content-Length' and 'ETag', without a body.
+		// The option is copied to the groups and subgroups created afterwards, and is disabled by default.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.WithAutoHEAD(true).Get("/users", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoints 'GET /users' and 'HEAD /users'
+		WithAutoHEAD(enabled bool) *router
+
+		// WithHost restricts the routes registered afterwards to the requests for the host, e.g. 'tenant1.example.com',
+		// prefixing their http.ServeMux patterns with it. An empty host removes the restriction.
+		// The host is copied to the groups and subgroups created afterwards. It panics if the host contains a '/'.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.SubGroup("").WithHost("tenant1.example.com").Get("/users", tenant1Handler)
+		//	superRouter.Get("/users", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request 'GET /users' with tenant1Handler for the host
+		//		'tenant1.example.com' and with handler for the other hosts
+		WithHost(host string) *router
 
 		AddMiddlewares(middleware ...MiddlewareFunc) *router
 
+		// AddGlobalMiddlewares adds middlewares that wrap every route registered afterwards on the router and on all
+		// its groups and subgroups, including those created before, or with Group and GroupWith, which drop the
+		// router middlewares. The global middlewares wrap the middlewares of the routes.
+		// Routes already registered keep the middlewares they were registered with.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddGlobalMiddlewares(middleware1)
+		//	superRouter.GroupWith("/users", middleware2).Get("", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+		//		wrapped in middleware1 and middleware2
+		AddGlobalMiddlewares(middlewares ...MiddlewareFunc) *router
+
+		// Use is an alias of AddMiddlewares.
+		Use(middlewares ...MiddlewareFunc) *router
+
+		// Middlewares lists the middlewares of the router, in the order they wrap the handlers.
+		//
+		// Returns:
+		//   - A copy of the router middlewares.
+		Middlewares() []MiddlewareFunc
+
+		// ClearMiddlewares removes every middleware from the router.
+		// Routes already registered keep the middlewares they were registered with.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1, middleware2).Get("/users", handler)
+		//	superRouter.ClearMiddlewares().Get("/health", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoints 'GET /users' wrapped in
+		//		middleware1 and middleware2, and 'GET /health' with 0 middlewares
+		ClearMiddlewares() *router
+
+		// InsertMiddlewareAt inserts the middleware at the index of the router middlewares,
+		// so it wraps the middlewares from that index onwards. An index equal to the number of middlewares appends it.
+		// Routes already registered keep the middlewares they were registered with.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//   - An error if the index is out of range.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1, middleware2)
+		//	superRouter.InsertMiddlewareAt(0, middleware3)
+		//	superRouter.Get("/users", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+		//		wrapped in middleware3, middleware1 and middleware2
+		InsertMiddlewareAt(index int, middleware MiddlewareFunc) (*router, error)
+
+		// RemoveMiddlewareAt removes the middleware at the index of the router middlewares.
+		// Routes already registered keep the middlewares they were registered with.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//   - An error if the index is out of range.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1, middleware2, middleware3)
+		//	superRouter.RemoveMiddlewareAt(1)
+		//	superRouter.Get("/users", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+		//		wrapped in middleware1 and middleware3
+		RemoveMiddlewareAt(index int) (*router, error)
+
+		// ReplaceMiddlewareAt replaces the middleware at the index of the router middlewares.
+		// Routes already registered keep the middlewares they were registered with.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//   - An error if the index is out of range.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1, middleware2)
+		//	superRouter.ReplaceMiddlewareAt(0, middleware3)
+		//	superRouter.Get("/users", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+		//		wrapped in middleware3 and middleware2
+		ReplaceMiddlewareAt(index int, middleware MiddlewareFunc) (*router, error)
+
+		// Routes lists the routes registered through the router, its groups and its subgroups, in registration order.
+		//
+		// Returns:
+		//   - A copy of the registered routes.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.Get("/login", handler).SubGroup("/users").WithHost("api.example.com").Post("/{id}", handler)
+		//	routes := superRouter.Routes()
+		//
+		//	# Result: [{Method:GET Pattern:/login FullPattern:GET /login Host:}
+		//		{Method:POST Pattern:/users/{id} FullPattern:POST api.example.com/users/{id} Host:api.example.com}]
+		Routes() []RouteInfo
+
+		// Walk calls fn for every route registered through the router, its groups and its subgroups,
+		// in registration order, with the number of middlewares the route was registered with.
+		// Walk stops at the first error returned by fn.
+		//
+		// Returns:
+		//   - The error returned by fn, if any.
+		//
+		// Example:
+		//
+		//	superRouter.Walk(func(method string, fullPattern string, middlewareCount int) error {
+		//		fmt.Printf("%s wrapped in %d middlewares\n", fullPattern, middlewareCount)
+		//		return nil
+		//	})
+		Walk(fn func(method string, fullPattern string, middlewareCount int) error) error
+
+		// GenerateOpenAPI creates an OpenAPI 3.0 JSON document with a path item for every pattern registered
+		// through the router, its groups and its subgroups, and an operation for every method of the pattern.
+		// The request and response schemas are empty objects, to be completed by hand.
+		//
+		// Returns:
+		//   - The JSON document.
+		//   - An error if the document cannot be encoded.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.Get("/users/{id}", handler).Put("/users/{id}", handler)
+		//	spec, err := superRouter.GenerateOpenAPI(supermuxer.OpenAPIInfo{Title: "Users API", Version: "1.0.0"})
+		//
+		//	# Result: an OpenAPI document with the 'get' and 'put' operations of the '/users/{id}' path
+		GenerateOpenAPI(info OpenAPIInfo) ([]byte, error)
+
+		// Mount registers every route of the sub router under the prefix, on top of the router base path.
+		// Each route is wrapped in the router middlewares first and then in the middlewares it was registered with.
+		// Routes restricted to a host with WithHost keep their host, while the others get the host of the router, if any.
+		// Routes registered on the sub router after Mount is called are not mounted. It panics if the sub router
+		// was not created by New or NewWithOptions.
+		//
+		// Returns:
+		//   - A reference to the router.
+		//
+		// Example:
+		//
+		//	usersRouter := supermuxer.New(http.NewServeMux())
+		//	usersRouter.AddMiddlewares(middleware2).Get("/users", handler)
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1).Mount("/api/v1", usersRouter)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /api/v1/users'
+		//		wrapped in middleware1 and middleware2
+		Mount(prefix string, sub Router) *router
+
 		// Group creates a group of routes for a base path without middlewares.
 		// The original router is not modified, as Group uses a copy.
 		//
@@ -40,6 +546,38 @@ type (
 		//	# Result: supermuxer configuration to handle the request for the endpoints 'GET /users' and 'POST /users/{id}' with 0 middlewares
 		Group(basePath string) *router
 
+		// Clone creates a copy of the router with the same base path and middlewares,
+		// registering its routes on the same http.ServeMux.
+		// The original router is not modified by changes to the middlewares of the clone.
+		//
+		// Returns:
+		//   - The cloned router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1)
+		//	superRouter.Clone().AddMiddlewares(middleware2).Get("/users", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+		//		wrapped in middleware1 and middleware2, while superRouter keeps only middleware1
+		Clone() Router
+
+		// GroupWith creates a group of routes for a base path, like Group, wrapped only in the given middlewares.
+		// The original router is not modified, as GroupWith uses a copy.
+		//
+		// Returns:
+		//   - A reference to the Group router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1, middleware2)
+		//	superRouter.GroupWith("/users", middleware3).Get("", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users' wrapped only in middleware3
+		GroupWith(basePath string, middlewares ...MiddlewareFunc) *router
+
 		// SubGroup creates a subgroup of routes for a base path that REUSES the middlewares defined in the original router.
 		// The original router is not modified, as SubGroup uses a copy.
 		//
@@ -55,14 +593,69 @@ type (
 		//	# Result: supermuxer configuration to handle the request for the endpoints 'GET /users' and 'POST /users/{id}'
 		//		each wrapped in middleware1 and middleware2
 		SubGroup(basePath string) *router
+
+		// Version creates a subgroup of routes, like SubGroup, for the '/v' + v base path
+		// that REUSES the middlewares defined in the original router.
+		// The original router is not modified, as Version uses a copy.
+		//
+		// Returns:
+		//   - A reference to the SubGroup router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1)
+		//	superRouter.Version("1").Get("/users", handler)
+		//	superRouter.Version("2").Get("/users", handlerV2)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoints 'GET /v1/users' and 'GET /v2/users'
+		//		each wrapped in middleware1
+		Version(v string) *router
+
+		// SubGroupWith creates a subgroup of routes for a base path, like SubGroup, that REUSES the middlewares
+		// defined in the original router followed by the given middlewares.
+		// The original router is not modified, as SubGroupWith uses a copy.
+		//
+		// Returns:
+		//   - A reference to the SubGroup router.
+		//
+		// Example:
+		//
+		//	superRouter := supermuxer.New(serveMux)
+		//	superRouter.AddMiddlewares(middleware1, middleware2)
+		//	superRouter.SubGroupWith("/admin", middleware3).Get("", handler)
+		//
+		//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /admin'
+		//		wrapped in middleware1, middleware2 and middleware3
+		SubGroupWith(basePath string, middlewares ...MiddlewareFunc) *router
 	}
 )
 
-func getFullPath(method string, basePath string, endpoint string) string {
-	fullPath := fmt.Sprintf("%s %s%s", method, basePath, endpoint)
+func middlewareIndexError(index int, length int) error {
+	return fmt.Errorf("supermuxer: middleware index %d out of range for %d middlewares", index, length)
+}
+
+func getFullPath(method string, host string, basePath string, endpoint string) string {
+	fullPath := fmt.Sprintf("%s %s%s%s", method, host, basePath, endpoint)
 	return fullPath
 }
 
+// RoutePattern returns the path of the pattern of the route that matched the request, without its method and
+// host, e.g. '/users/{id}' for 'GET example.com/users/{id}'. It is empty when the request matched no pattern.
+// The middlewares use it to describe the requests with a bounded set of values, e.g. as metric labels.
+func RoutePattern(r *http.Request) string {
+	pattern := r.Pattern
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+
+	return pattern
+}
+
 func handlerWithMiddlewares(handler http.HandlerFunc, middlewares []MiddlewareFunc) http.HandlerFunc {
 	if len(middlewares) <= 0 {
 		return handler
@@ -77,11 +670,193 @@ func handlerWithMiddlewares(handler http.HandlerFunc, middlewares []MiddlewareFu
 	return next
 }
 
-func setRoute(r *router, method string, path string, handler http.HandlerFunc) *router {
-	fullPath := getFullPath(method, r.basePath, path)
-	wrappedHandler := handlerWithMiddlewares(handler, r.middlewares)
+// Chain composes the middlewares into a single middleware, where the first middleware is the outermost.
+//
+// Returns:
+//   - A middleware equivalent to adding all the middlewares in order.
+//
+// Example:
+//
+//	authChain := supermuxer.Chain(middleware1, middleware2)
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(authChain, middleware3).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		wrapped in middleware1, middleware2 and middleware3
+func Chain(middlewares ...MiddlewareFunc) MiddlewareFunc {
+	middlewares = slices.Clone(middlewares)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return handlerWithMiddlewares(next, middlewares)
+	}
+}
+
+// ConditionalMiddleware creates a middleware that only applies the middleware to the requests
+// for which the predicate returns true. The other requests go directly to the next handler.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares or composed with Chain.
+//
+// Example:
+//
+//	isExternal := func(r *http.Request) bool { return r.Header.Get("X-Internal") == "" }
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.ConditionalMiddleware(isExternal, authMiddleware)).Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users'
+//		wrapped in authMiddleware only when the 'X-Internal' header is missing
+func ConditionalMiddleware(predicate func(*http.Request) bool, middleware MiddlewareFunc) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		wrapped := middleware(next)
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			if predicate(r) {
+				wrapped(w, r)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// allowedMethods lists the methods, other than the request method, that have a route matching the request path.
+func (f *fallback) allowedMethods(r *http.Request) []string {
+	allowed := []string{}
+
+	for _, method := range append(anyMethods, http.MethodConnect) {
+		if method == r.Method {
+			continue
+		}
+
+		probe := *r
+		probe.Method = method
+
+		if _, pattern := f.mux.Handler(&probe); matchesPath(pattern) {
+			allowed = append(allowed, method)
+		}
+	}
+
+	return allowed
+}
+
+// matchesPath reports whether the pattern matched by a probe of the fallback is a route for the request path,
+// rather than the fallback itself or a catch-all route like 'GET /' or 'GET /{path...}', which match every path.
+func matchesPath(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+
+	path := RoutePattern(&http.Request{Pattern: pattern})
+	if path == "/" {
+		return false
+	}
+
+	wildcard, ok := strings.CutPrefix(path, "/{")
+	return !ok || !strings.HasSuffix(wildcard, "...}") || strings.Contains(wildcard, "/")
+}
+
+// trailingSlashTarget finds the URL, with or without the trailing slash, that has a route for the request method.
+func (f *fallback) trailingSlashTarget(r *http.Request) (string, bool) {
+	if !f.trailingSlashRedirect || r.URL.Path == "/" || r.URL.Path == "" {
+		return "", false
+	}
+
+	target := *r.URL
+	target.RawPath = ""
+
+	if strings.HasSuffix(target.Path, "/") {
+		target.Path = strings.TrimSuffix(target.Path, "/")
+	} else {
+		target.Path += "/"
+	}
+
+	probe := *r
+	probe.URL = &target
+
+	if _, pattern := f.mux.Handler(&probe); !matchesPath(pattern) {
+		return "", false
+	}
+
+	return target.RequestURI(), true
+}
+
+func (f *fallback) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if target, ok := f.trailingSlashTarget(r); ok {
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+
+		http.Redirect(w, r, target, code)
+		return
+	}
+
+	if allowed := f.allowedMethods(r); len(allowed) > 0 {
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+		if f.methodNotAllowed == nil {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		f.methodNotAllowed(w, r)
+		return
+	}
+
+	if f.notFound == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	f.notFound(w, r)
+}
+
+func registerFallback(r *router) {
+	if r.fallback.registered {
+		return
+	}
+
+	r.mux.Handle("/", r.fallback)
+	r.fallback.registered = true
+}
+
+// wrapHandler wraps the handler in the middlewares and then in the recovery middleware of the router, if any.
+func wrapHandler(r *router, handler http.HandlerFunc, middlewares []MiddlewareFunc) http.HandlerFunc {
+	wrappedHandler := handlerWithMiddlewares(handler, middlewares)
+
+	if r.recovery != nil {
+		wrappedHandler = r.recovery(wrappedHandler)
+	}
+
+	return wrappedHandler
+}
+
+func registerRoute(r *router, method string, path string, handler http.HandlerFunc, middlewares []MiddlewareFunc) {
+	fullPath := getFullPath(method, r.host, r.basePath, path)
+	if _, exists := r.registeredPatterns[fullPath]; exists {
+		panic(fmt.Sprintf("supermuxer: route %q is already registered", fullPath))
+	}
+
+	middlewares = append(slices.Clone(*r.globalMiddlewares), middlewares...)
 
-	r.mux.HandleFunc(fullPath, wrappedHandler)
+	r.mux.HandleFunc(fullPath, wrapHandler(r, handler, middlewares))
+	r.registeredPatterns[fullPath] = struct{}{}
+
+	*r.routes = append(*r.routes, route{
+		RouteInfo: RouteInfo{
+			Method:      method,
+			Pattern:     r.basePath + path,
+			FullPattern: fullPath,
+			Host:        r.host,
+		},
+		handler:     handler,
+		middlewares: slices.Clone(middlewares),
+	})
+}
+
+func setRoute(r *router, method string, path string, handler http.HandlerFunc) *router {
+	registerRoute(r, method, path, handler, r.middlewares)
 	return r
 }
 
@@ -94,6 +869,20 @@ func (r *router) Group(basePath string) *router {
 	return &rCopy
 }
 
+func (r *router) Clone() Router {
+	rCopy := *r
+	rCopy.middlewares = slices.Clone(r.middlewares)
+
+	return &rCopy
+}
+
+func (r *router) GroupWith(basePath string, middlewares ...MiddlewareFunc) *router {
+	rCopy := r.Group(basePath)
+	rCopy.middlewares = append(rCopy.middlewares, middlewares...)
+
+	return rCopy
+}
+
 func (r *router) SubGroup(basePath string) *router {
 	rCopy := *r
 
@@ -102,8 +891,25 @@ func (r *router) SubGroup(basePath string) *router {
 	return &rCopy
 }
 
+func (r *router) Version(v string) *router {
+	return r.SubGroup("/v" + v)
+}
+
+func (r *router) SubGroupWith(basePath string, middlewares ...MiddlewareFunc) *router {
+	rCopy := r.SubGroup(basePath)
+	rCopy.middlewares = append(slices.Clone(rCopy.middlewares), middlewares...)
+
+	return rCopy
+}
+
 func (r *router) Get(path string, handler http.HandlerFunc) *router {
-	return setRoute(r, http.MethodGet, path, handler)
+	setRoute(r, http.MethodGet, path, handler)
+
+	if r.autoHEAD {
+		setRoute(r, http.MethodHead, path, handler)
+	}
+
+	return r
 }
 
 func (r *router) Post(path string, handler http.HandlerFunc) *router {
@@ -122,14 +928,335 @@ func (r *router) Delete(path string, handler http.HandlerFunc) *router {
 	return setRoute(r, http.MethodDelete, path, handler)
 }
 
+func (r *router) Head(path string, handler http.HandlerFunc) *router {
+	return setRoute(r, http.MethodHead, path, handler)
+}
+
+func (r *router) Options(path string, handler http.HandlerFunc) *router {
+	return setRoute(r, http.MethodOptions, path, handler)
+}
+
+func (r *router) Trace(path string, handler http.HandlerFunc) *router {
+	return setRoute(r, http.MethodTrace, path, handler)
+}
+
+func (r *router) Connect(path string, handler http.HandlerFunc) *router {
+	return setRoute(r, http.MethodConnect, path, handler)
+}
+
+func (r *router) Any(path string, handler http.HandlerFunc) *router {
+	for _, method := range anyMethods {
+		setRoute(r, method, path, handler)
+	}
+
+	return r
+}
+
+func (r *router) Handle(method string, path string, handler http.Handler) *router {
+	return setRoute(r, method, path, handler.ServeHTTP)
+}
+
+func (r *router) HandleGet(path string, handler http.Handler) *router {
+	return r.Handle(http.MethodGet, path, handler)
+}
+
+func (r *router) HandlePost(path string, handler http.Handler) *router {
+	return r.Handle(http.MethodPost, path, handler)
+}
+
+func (r *router) HandlePut(path string, handler http.Handler) *router {
+	return r.Handle(http.MethodPut, path, handler)
+}
+
+func (r *router) HandlePatch(path string, handler http.Handler) *router {
+	return r.Handle(http.MethodPatch, path, handler)
+}
+
+func (r *router) HandleDelete(path string, handler http.Handler) *router {
+	return r.Handle(http.MethodDelete, path, handler)
+}
+
+func (r *router) HandleOptions(path string, handler http.Handler) *router {
+	return r.Handle(http.MethodOptions, path, handler)
+}
+
+func (r *router) Redirect(from string, to string, code int) *router {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		panic(fmt.Sprintf("supermuxer: invalid redirect code %d for %q, expected 301, 302, 307 or 308", code, from))
+	}
+
+	return r.Any(from, func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, to, code)
+	})
+}
+
+func (r *router) StaticFiles(prefix string, dir string) *router {
+	prefix = strings.TrimSuffix(prefix, "/")
+	fileServer := http.StripPrefix(r.basePath+prefix, http.FileServer(http.Dir(dir)))
+
+	return r.HandleGet(prefix+"/{path...}", fileServer)
+}
+
+func (r *router) RouteNamed(method string, name string, path string, handler http.HandlerFunc) *router {
+	if _, exists := r.names[name]; exists {
+		panic(fmt.Sprintf("supermuxer: route name %q is already registered", name))
+	}
+
+	setRoute(r, method, path, handler)
+	r.names[name] = r.basePath + path
+
+	return r
+}
+
+func (r *router) GetNamed(name string, path string, handler http.HandlerFunc) *router {
+	return r.RouteNamed(http.MethodGet, name, path, handler)
+}
+
+func (r *router) PostNamed(name string, path string, handler http.HandlerFunc) *router {
+	return r.RouteNamed(http.MethodPost, name, path, handler)
+}
+
+func (r *router) PutNamed(name string, path string, handler http.HandlerFunc) *router {
+	return r.RouteNamed(http.MethodPut, name, path, handler)
+}
+
+func (r *router) PatchNamed(name string, path string, handler http.HandlerFunc) *router {
+	return r.RouteNamed(http.MethodPatch, name, path, handler)
+}
+
+func (r *router) DeleteNamed(name string, path string, handler http.HandlerFunc) *router {
+	return r.RouteNamed(http.MethodDelete, name, path, handler)
+}
+
+func (r *router) URL(name string, params ...string) (string, error) {
+	template, exists := r.names[name]
+	if !exists {
+		return "", fmt.Errorf("supermuxer: unknown route name %q", name)
+	}
+
+	var url strings.Builder
+	used := 0
+
+	for {
+		start := strings.Index(template, "{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		url.WriteString(template[:start])
+		wildcard := template[start+1 : end]
+		template = template[end+1:]
+
+		// '{$}' only anchors the end of the path and is not a param.
+		if wildcard == "$" {
+			continue
+		}
+
+		if used >= len(params) {
+			return "", fmt.Errorf("supermuxer: route %q expects more than %d params", name, len(params))
+		}
+
+		if strings.HasSuffix(wildcard, "...") {
+			segments := strings.Split(params[used], "/")
+			for i, segment := range segments {
+				segments[i] = neturl.PathEscape(segment)
+			}
+			url.WriteString(strings.Join(segments, "/"))
+		} else {
+			url.WriteString(neturl.PathEscape(params[used]))
+		}
+		used++
+	}
+
+	if used != len(params) {
+		return "", fmt.Errorf("supermuxer: route %q expects %d params, got %d", name, used, len(params))
+	}
+
+	url.WriteString(template)
+	return url.String(), nil
+}
+
+func (r *router) NotFound(handler http.HandlerFunc) *router {
+	r.fallback.notFound = wrapHandler(r, handler, append(slices.Clone(*r.globalMiddlewares), r.middlewares...))
+	registerFallback(r)
+
+	return r
+}
+
+func (r *router) MethodNotAllowed(handler http.HandlerFunc) *router {
+	r.fallback.methodNotAllowed = wrapHandler(r, handler, append(slices.Clone(*r.globalMiddlewares), r.middlewares...))
+	registerFallback(r)
+
+	return r
+}
+
+func (r *router) WithTrailingSlashRedirect(redirect bool) *router {
+	r.fallback.trailingSlashRedirect = redirect
+	if redirect {
+		registerFallback(r)
+	}
+
+	return r
+}
+
+func (r *router) WithAutoHEAD(enabled bool) *router {
+	r.autoHEAD = enabled
+	return r
+}
+
+func (r *router) WithHost(host string) *router {
+	if strings.Contains(host, "/") {
+		panic(fmt.Sprintf("supermuxer: invalid host %q", host))
+	}
+
+	r.host = host
+	return r
+}
+
 func (r *router) AddMiddlewares(middlewares ...MiddlewareFunc) *router {
 	r.middlewares = append(r.middlewares, middlewares...)
 	return r
 }
 
+func (r *router) AddGlobalMiddlewares(middlewares ...MiddlewareFunc) *router {
+	*r.globalMiddlewares = append(slices.Clone(*r.globalMiddlewares), middlewares...)
+	return r
+}
+
+func (r *router) Use(middlewares ...MiddlewareFunc) *router {
+	return r.AddMiddlewares(middlewares...)
+}
+
+func (r *router) Middlewares() []MiddlewareFunc {
+	return slices.Clone(r.middlewares)
+}
+
+func (r *router) ClearMiddlewares() *router {
+	r.middlewares = []MiddlewareFunc{}
+	return r
+}
+
+func (r *router) InsertMiddlewareAt(index int, middleware MiddlewareFunc) (*router, error) {
+	if index < 0 || index > len(r.middlewares) {
+		return r, middlewareIndexError(index, len(r.middlewares))
+	}
+
+	r.middlewares = slices.Insert(slices.Clone(r.middlewares), index, middleware)
+	return r, nil
+}
+
+func (r *router) RemoveMiddlewareAt(index int) (*router, error) {
+	if index < 0 || index >= len(r.middlewares) {
+		return r, middlewareIndexError(index, len(r.middlewares))
+	}
+
+	r.middlewares = slices.Delete(slices.Clone(r.middlewares), index, index+1)
+	return r, nil
+}
+
+func (r *router) ReplaceMiddlewareAt(index int, middleware MiddlewareFunc) (*router, error) {
+	if index < 0 || index >= len(r.middlewares) {
+		return r, middlewareIndexError(index, len(r.middlewares))
+	}
+
+	r.middlewares = slices.Clone(r.middlewares)
+	r.middlewares[index] = middleware
+	return r, nil
+}
+
+func (r *router) Routes() []RouteInfo {
+	routes := make([]RouteInfo, 0, len(*r.routes))
+
+	for _, route := range *r.routes {
+		routes = append(routes, route.RouteInfo)
+	}
+
+	return routes
+}
+
+func (r *router) Walk(fn func(method string, fullPattern string, middlewareCount int) error) error {
+	for _, route := range *r.routes {
+		if err := fn(route.Method, route.FullPattern, len(route.middlewares)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *router) Mount(prefix string, sub Router) *router {
+	subRouter, ok := sub.(*router)
+	if !ok {
+		panic(fmt.Sprintf("supermuxer: cannot mount %T, only routers created by New or NewWithOptions can be mounted", sub))
+	}
+
+	for _, route := range *subRouter.routes {
+		target := r
+		if route.Host != "" {
+			hostRouter := *r
+			hostRouter.host = route.Host
+			target = &hostRouter
+		}
+
+		middlewares := append(slices.Clone(r.middlewares), route.middlewares...)
+		registerRoute(target, route.Method, prefix+route.Pattern, route.handler, middlewares)
+	}
+
+	return r
+}
+
+func (r *router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mux.ServeHTTP(w, req)
+}
+
 func New(mux *http.ServeMux) Router {
-	return &router{
+	return NewWithOptions(mux, RouterOptions{})
+}
+
+// NewWithOptions creates a router for the http.ServeMux, like New, configured with the options.
+//
+// Example:
+//
+//	superRouter := supermuxer.NewWithOptions(serveMux, supermuxer.RouterOptions{AutoHEAD: true, NotFoundHandler: notFoundHandler})
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoints 'GET /users' and 'HEAD /users',
+//		and the requests for unknown endpoints with notFoundHandler
+func NewWithOptions(mux *http.ServeMux, opts RouterOptions) Router {
+	r := &router{
 		mux:         mux,
 		middlewares: []MiddlewareFunc{},
+		routes:      &[]route{},
+		names:       map[string]string{},
+		fallback:    &fallback{mux: mux},
+
+		globalMiddlewares:  &[]MiddlewareFunc{},
+		registeredPatterns: map[string]struct{}{},
+	}
+
+	if opts.PanicHandler != nil {
+		r.recovery = PanicRecovery(opts.PanicHandler)
 	}
+
+	r.AddGlobalMiddlewares(opts.GlobalMiddlewares...)
+	r.WithAutoHEAD(opts.AutoHEAD)
+	r.WithTrailingSlashRedirect(opts.TrailingSlashRedirect)
+
+	if opts.NotFoundHandler != nil {
+		r.NotFound(opts.NotFoundHandler)
+	}
+
+	if opts.MethodNotAllowedHandler != nil {
+		r.MethodNotAllowed(opts.MethodNotAllowedHandler)
+	}
+
+	return r
 }
@@ -0,0 +1,1080 @@
+package supermuxer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordingMiddleware creates a middleware that appends its name to the calls before calling the next handler.
+func recordingMiddleware(name string, calls *[]string) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*calls = append(*calls, name)
+			next(w, r)
+		}
+	}
+}
+
+// textHandler creates a handler that writes the body.
+func textHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+// serve sends a request for the method and target to the handler and returns the recorded response.
+func serve(handler http.Handler, method string, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+
+	return rec
+}
+
+// assertPanics fails the test unless fn panics with a message containing want.
+func assertPanics(t *testing.T, want string, fn func()) {
+	t.Helper()
+
+	defer func() {
+		t.Helper()
+
+		recovered := recover()
+		if recovered == nil {
+			t.Fatalf("no panic, want a panic containing %q", want)
+		}
+
+		if message := fmt.Sprint(recovered); !strings.Contains(message, want) {
+			t.Fatalf("panic = %q, want it to contain %q", message, want)
+		}
+	}()
+
+	fn()
+}
+
+func TestHead(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux)
+
+	superRouter.AddMiddlewares(recordingMiddleware("before", &calls)).Head("/users", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+		w.Header().Set("X-Total-Count", "2")
+	})
+	superRouter.AddMiddlewares(recordingMiddleware("after", &calls))
+
+	if _, pattern := mux.Handler(httptest.NewRequest(http.MethodHead, "/users", nil)); pattern != "HEAD /users" {
+		t.Fatalf("pattern = %q, want %q", pattern, "HEAD /users")
+	}
+
+	rec := serve(mux, http.MethodHead, "/users")
+	if rec.Code != http.StatusOK || rec.Header().Get("X-Total-Count") != "2" {
+		t.Fatalf("response = %d %v, want 200 with the X-Total-Count header", rec.Code, rec.Header())
+	}
+
+	if want := []string{"before", "handler"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	mux := http.NewServeMux()
+	New(mux).Group("/api").Options("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", "GET, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if _, pattern := mux.Handler(httptest.NewRequest(http.MethodOptions, "/api/users", nil)); pattern != "OPTIONS /api/users" {
+		t.Fatalf("pattern = %q, want %q", pattern, "OPTIONS /api/users")
+	}
+
+	rec := serve(mux, http.MethodOptions, "/api/users")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Allow") != "GET, OPTIONS" {
+		t.Fatalf("response = %d %v, want 204 with the Allow header", rec.Code, rec.Header())
+	}
+}
+
+func TestTrace(t *testing.T) {
+	mux := http.NewServeMux()
+	New(mux).Trace("/debug", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "message/http")
+		r.Write(w)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodTrace, server.URL+"/debug", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "message/http" {
+		t.Fatalf("response = %d %v, want 200 with the message/http content type", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(recordingMiddleware("middleware", &calls)).Connect("/tunnel", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+
+	if rec := serve(mux, http.MethodConnect, "/tunnel"); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if want := []string{"middleware", "handler"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestAny(t *testing.T) {
+	var methods []string
+	mux := http.NewServeMux()
+	New(mux).Any("/echo", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+	})
+
+	for _, method := range anyMethods {
+		if rec := serve(mux, method, "/echo"); rec.Code != http.StatusOK {
+			t.Errorf("%s status = %d, want %d", method, rec.Code, http.StatusOK)
+		}
+	}
+
+	if !slices.Equal(methods, anyMethods) {
+		t.Fatalf("methods = %v, want %v", methods, anyMethods)
+	}
+
+	if _, pattern := mux.Handler(httptest.NewRequest(http.MethodConnect, "/echo", nil)); pattern != "" {
+		t.Fatalf("CONNECT pattern = %q, want none", pattern)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(recordingMiddleware("middleware", &calls))
+
+	superRouter.Handle(http.MethodGet, "/assets/", http.StripPrefix("/assets/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	})))
+	superRouter.HandlePost("/users", textHandler("created"))
+
+	if rec := serve(mux, http.MethodGet, "/assets/app.js"); rec.Body.String() != "app.js" {
+		t.Fatalf("GET body = %q, want %q", rec.Body.String(), "app.js")
+	}
+
+	if rec := serve(mux, http.MethodPost, "/users"); rec.Body.String() != "created" {
+		t.Fatalf("POST body = %q, want %q", rec.Body.String(), "created")
+	}
+
+	if want := []string{"middleware", "middleware"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	superRouter := New(http.NewServeMux())
+	superRouter.Get("/login", textHandler("login"))
+	superRouter.Group("/admin").Delete("/cache", textHandler("cleared"))
+	superRouter.SubGroup("/users").Post("/{id}", textHandler("updated"))
+	superRouter.SubGroup("/tenants").WithHost("api.example.com").Get("/{id}", textHandler("tenant"))
+
+	want := []RouteInfo{
+		{Method: http.MethodGet, Pattern: "/login", FullPattern: "GET /login"},
+		{Method: http.MethodDelete, Pattern: "/admin/cache", FullPattern: "DELETE /admin/cache"},
+		{Method: http.MethodPost, Pattern: "/users/{id}", FullPattern: "POST /users/{id}"},
+		{Method: http.MethodGet, Pattern: "/tenants/{id}", FullPattern: "GET api.example.com/tenants/{id}", Host: "api.example.com"},
+	}
+
+	routes := superRouter.Routes()
+	if !slices.Equal(routes, want) {
+		t.Fatalf("routes = %+v, want %+v", routes, want)
+	}
+
+	routes[0].Pattern = "/changed"
+	if superRouter.Routes()[0].Pattern != "/login" {
+		t.Fatal("changing the returned routes changed the router routes")
+	}
+}
+
+func TestClearMiddlewares(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(recordingMiddleware("middleware1", &calls), recordingMiddleware("middleware2", &calls))
+
+	superRouter.Get("/users", textHandler("users"))
+	superRouter.ClearMiddlewares().Get("/health", textHandler("ok"))
+
+	serve(mux, http.MethodGet, "/health")
+	if len(calls) != 0 {
+		t.Fatalf("calls after clearing = %v, want none", calls)
+	}
+
+	serve(mux, http.MethodGet, "/users")
+	if want := []string{"middleware1", "middleware2"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls before clearing = %v, want %v", calls, want)
+	}
+}
+
+func TestRemoveMiddlewareAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		index int
+		want  []string
+	}{
+		{name: "beginning", index: 0, want: []string{"middleware2", "middleware3"}},
+		{name: "middle", index: 1, want: []string{"middleware1", "middleware3"}},
+		{name: "end", index: 2, want: []string{"middleware1", "middleware2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			mux := http.NewServeMux()
+			superRouter := New(mux).AddMiddlewares(
+				recordingMiddleware("middleware1", &calls),
+				recordingMiddleware("middleware2", &calls),
+				recordingMiddleware("middleware3", &calls),
+			)
+
+			if _, err := superRouter.RemoveMiddlewareAt(tt.index); err != nil {
+				t.Fatal(err)
+			}
+
+			superRouter.Get("/users", textHandler("users"))
+			serve(mux, http.MethodGet, "/users")
+
+			if !slices.Equal(calls, tt.want) {
+				t.Fatalf("calls = %v, want %v", calls, tt.want)
+			}
+		})
+	}
+
+	t.Run("out of range", func(t *testing.T) {
+		superRouter := New(http.NewServeMux()).AddMiddlewares(recordingMiddleware("middleware1", new([]string)))
+
+		for _, index := range []int{-1, 1} {
+			if _, err := superRouter.RemoveMiddlewareAt(index); err == nil {
+				t.Errorf("RemoveMiddlewareAt(%d) error = nil, want an error", index)
+			}
+		}
+
+		if len(superRouter.middlewares) != 1 {
+			t.Fatalf("middlewares = %d, want 1", len(superRouter.middlewares))
+		}
+	})
+}
+
+func TestReplaceMiddlewareAt(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(recordingMiddleware("middleware1", &calls), recordingMiddleware("middleware2", &calls))
+
+	if _, err := superRouter.ReplaceMiddlewareAt(0, recordingMiddleware("middleware3", &calls)); err != nil {
+		t.Fatal(err)
+	}
+
+	superRouter.Get("/users", textHandler("users"))
+	serve(mux, http.MethodGet, "/users")
+
+	if want := []string{"middleware3", "middleware2"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	if _, err := superRouter.ReplaceMiddlewareAt(2, recordingMiddleware("middleware4", &calls)); err == nil {
+		t.Fatal("ReplaceMiddlewareAt(2) error = nil, want an error")
+	}
+}
+
+func TestURL(t *testing.T) {
+	mux := http.NewServeMux()
+	superRouter := New(mux)
+	superRouter.SubGroup("/users").GetNamed("user", "/{id}", textHandler("user"))
+	superRouter.PostNamed("files", "/files/{bucket}/{path...}", textHandler("file"))
+	superRouter.GetNamed("home", "/{$}", textHandler("home"))
+
+	tests := []struct {
+		name   string
+		params []string
+		want   string
+	}{
+		{name: "user", params: []string{"42"}, want: "/users/42"},
+		{name: "user", params: []string{"a b/c"}, want: "/users/a%20b%2Fc"},
+		{name: "files", params: []string{"docs", "2024/q1 report.pdf"}, want: "/files/docs/2024/q1%20report.pdf"},
+		{name: "home", want: "/"},
+	}
+
+	for _, tt := range tests {
+		url, err := superRouter.URL(tt.name, tt.params...)
+		if err != nil || url != tt.want {
+			t.Errorf("URL(%q, %q) = %q, %v, want %q", tt.name, tt.params, url, err, tt.want)
+		}
+	}
+
+	if rec := serve(mux, http.MethodGet, "/users/42"); rec.Body.String() != "user" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "user")
+	}
+
+	for _, params := range [][]string{{}, {"1", "2"}} {
+		if _, err := superRouter.URL("user", params...); err == nil {
+			t.Errorf("URL(%q, %q) error = nil, want an error", "user", params)
+		}
+	}
+
+	if _, err := superRouter.URL("unknown"); err == nil {
+		t.Error("URL of an unknown name error = nil, want an error")
+	}
+
+	assertPanics(t, `route name "user" is already registered`, func() {
+		superRouter.GetNamed("user", "/profiles/{id}", textHandler("profile"))
+	})
+}
+
+func TestNotFound(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	New(mux).Get("/users", textHandler("users")).AddMiddlewares(recordingMiddleware("middleware", &calls)).NotFound(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("custom not found"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusNotFound || string(body) != "custom not found" {
+		t.Fatalf("response = %d %q, want 404 %q", resp.StatusCode, body, "custom not found")
+	}
+
+	if want := []string{"middleware"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	t.Run("default handler", func(t *testing.T) {
+		mux := http.NewServeMux()
+		New(mux).Get("/users", textHandler("users")).NotFound(textHandler("not found"))
+
+		rec := serve(mux, http.MethodPost, "/users")
+		if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != "GET, HEAD" {
+			t.Fatalf("response = %d %v, want 405 with 'Allow: GET, HEAD'", rec.Code, rec.Header())
+		}
+	})
+
+	t.Run("custom handler", func(t *testing.T) {
+		var calls []string
+		mux := http.NewServeMux()
+		superRouter := New(mux).AddGlobalMiddlewares(recordingMiddleware("global", &calls))
+		superRouter.Get("/users", textHandler("users")).MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			w.Write([]byte("custom method not allowed"))
+		})
+
+		rec := serve(mux, http.MethodDelete, "/users")
+		if rec.Code != http.StatusMethodNotAllowed || rec.Body.String() != "custom method not allowed" {
+			t.Fatalf("response = %d %q, want 405 %q", rec.Code, rec.Body.String(), "custom method not allowed")
+		}
+
+		if want := []string{"global"}; !slices.Equal(calls, want) {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	})
+
+	t.Run("catch-all routes", func(t *testing.T) {
+		mux := http.NewServeMux()
+		superRouter := New(mux).Get("/{path...}", textHandler("spa")).Post("/users", textHandler("created"))
+		superRouter.MethodNotAllowed(textHandler("method not allowed"))
+
+		if rec := serve(mux, http.MethodPut, "/about"); rec.Code != http.StatusNotFound {
+			t.Fatalf("status for a path only matched by a catch-all route = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+
+		if rec := serve(mux, http.MethodPut, "/users"); rec.Code != http.StatusOK || rec.Body.String() != "method not allowed" {
+			t.Fatalf("response = %d %q, want the method not allowed handler", rec.Code, rec.Body.String())
+		}
+	})
+}
+
+func TestUse(t *testing.T) {
+	var useCalls, addCalls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux)
+
+	superRouter.Group("/use").
+		Use(recordingMiddleware("middleware1", &useCalls), recordingMiddleware("middleware2", &useCalls)).
+		Use(recordingMiddleware("middleware3", &useCalls)).
+		Get("", textHandler("use"))
+	superRouter.Group("/add").
+		AddMiddlewares(recordingMiddleware("middleware1", &addCalls), recordingMiddleware("middleware2", &addCalls)).
+		AddMiddlewares(recordingMiddleware("middleware3", &addCalls)).
+		Get("", textHandler("add"))
+
+	serve(mux, http.MethodGet, "/use")
+	serve(mux, http.MethodGet, "/add")
+
+	if want := []string{"middleware1", "middleware2", "middleware3"}; !slices.Equal(useCalls, want) || !slices.Equal(addCalls, want) {
+		t.Fatalf("Use calls = %v, AddMiddlewares calls = %v, want %v", useCalls, addCalls, want)
+	}
+}
+
+func TestMount(t *testing.T) {
+	var calls []string
+	usersRouter := New(http.NewServeMux())
+	usersRouter.AddMiddlewares(recordingMiddleware("users", &calls)).Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("user " + r.PathValue("id")))
+	})
+	usersRouter.Post("/users", textHandler("created"))
+
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(recordingMiddleware("api", &calls)).Mount("/api/v1", usersRouter)
+
+	if rec := serve(mux, http.MethodGet, "/api/v1/users/42"); rec.Body.String() != "user 42" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "user 42")
+	}
+
+	if want := []string{"api", "users"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	if rec := serve(mux, http.MethodPost, "/api/v1/users"); rec.Body.String() != "created" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "created")
+	}
+
+	type otherRouter struct{ Router }
+	assertPanics(t, "cannot mount", func() {
+		New(http.NewServeMux()).Mount("/api", otherRouter{})
+	})
+}
+
+func TestWithHost(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(recordingMiddleware("global", &calls))
+	superRouter.Get("/users", textHandler("default users"))
+	superRouter.SubGroup("").WithHost("tenant1.example.com").Get("/users", textHandler("tenant1 users"))
+	superRouter.SubGroup("/api").WithHost("tenant2.example.com").Get("/users", textHandler("tenant2 users"))
+
+	sub := New(http.NewServeMux())
+	sub.Get("/orders", textHandler("tenant1 orders"))
+	sub.SubGroup("").WithHost("tenant2.example.com").Get("/orders", textHandler("tenant2 orders"))
+	superRouter.SubGroup("").WithHost("tenant1.example.com").Mount("/shop", sub)
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		host   string
+		path   string
+		status int
+		body   string
+	}{
+		{host: "tenant1.example.com", path: "/users", status: http.StatusOK, body: "tenant1 users"},
+		{host: "tenant2.example.com", path: "/api/users", status: http.StatusOK, body: "tenant2 users"},
+		{host: "tenant2.example.com", path: "/users", status: http.StatusOK, body: "default users"},
+		{host: "other.example.com", path: "/users", status: http.StatusOK, body: "default users"},
+		{host: "other.example.com", path: "/api/users", status: http.StatusNotFound},
+		{host: "tenant1.example.com", path: "/shop/orders", status: http.StatusOK, body: "tenant1 orders"},
+		{host: "tenant2.example.com", path: "/shop/orders", status: http.StatusOK, body: "tenant2 orders"},
+		{host: "other.example.com", path: "/shop/orders", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host+tt.path, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Host = tt.host
+
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+
+			if tt.body != "" && string(body) != tt.body {
+				t.Fatalf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+
+	calls = nil
+	serve(mux, http.MethodGet, "http://tenant1.example.com/users")
+	if want := []string{"global"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	assertPanics(t, `invalid host "example.com/users"`, func() {
+		New(http.NewServeMux()).WithHost("example.com/users")
+	})
+}
+
+func TestWithTrailingSlashRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	superRouter := New(mux).WithTrailingSlashRedirect(true)
+	superRouter.Get("/users", textHandler("users")).Post("/orders", textHandler("created"))
+
+	tests := []struct {
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		{method: http.MethodGet, target: "/users/", code: http.StatusMovedPermanently, location: "/users"},
+		{method: http.MethodGet, target: "/users/?page=2", code: http.StatusMovedPermanently, location: "/users?page=2"},
+		{method: http.MethodPost, target: "/orders/", code: http.StatusPermanentRedirect, location: "/orders"},
+		{method: http.MethodGet, target: "/unknown/", code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		rec := serve(mux, tt.method, tt.target)
+		if rec.Code != tt.code || rec.Header().Get("Location") != tt.location {
+			t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.target, rec.Code, rec.Header().Get("Location"), tt.code, tt.location)
+		}
+	}
+
+	disabledMux := http.NewServeMux()
+	New(disabledMux).Get("/users", textHandler("users"))
+	if rec := serve(disabledMux, http.MethodGet, "/users/"); rec.Code != http.StatusNotFound {
+		t.Fatalf("status without the option = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	inner := Chain(recordingMiddleware("middleware2", &calls), recordingMiddleware("middleware3", &calls))
+	outer := Chain(recordingMiddleware("middleware1", &calls), inner, recordingMiddleware("middleware4", &calls))
+
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(outer, recordingMiddleware("middleware5", &calls)).Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+	serve(mux, http.MethodGet, "/users")
+
+	if want := []string{"middleware1", "middleware2", "middleware3", "middleware4", "middleware5", "handler"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	Chain()(textHandler("users"))(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if len(calls) != 0 {
+		t.Fatalf("calls of an empty chain = %v, want none", calls)
+	}
+}
+
+func TestGroupWith(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(recordingMiddleware("parent", &calls))
+	superRouter.GroupWith("/admin", recordingMiddleware("admin1", &calls), recordingMiddleware("admin2", &calls)).Get("/stats", textHandler("stats"))
+
+	if rec := serve(mux, http.MethodGet, "/admin/stats"); rec.Body.String() != "stats" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "stats")
+	}
+
+	if want := []string{"admin1", "admin2"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	if len(superRouter.middlewares) != 1 {
+		t.Fatalf("parent middlewares = %d, want 1", len(superRouter.middlewares))
+	}
+}
+
+func TestSubGroupWith(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(recordingMiddleware("parent", &calls))
+	users := superRouter.SubGroupWith("/users", recordingMiddleware("users", &calls))
+	users.SubGroupWith("/{id}", recordingMiddleware("user", &calls)).Get("/orders", textHandler("orders"))
+	users.Get("", textHandler("users"))
+
+	if rec := serve(mux, http.MethodGet, "/users/42/orders"); rec.Body.String() != "orders" {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), "orders")
+	}
+
+	if want := []string{"parent", "users", "user"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	serve(mux, http.MethodGet, "/users")
+	if want := []string{"parent", "users"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls of the parent subgroup = %v, want %v", calls, want)
+	}
+}
+
+func TestRouterServeHTTP(t *testing.T) {
+	superRouter := New(http.NewServeMux())
+	superRouter.Get("/users", textHandler("users"))
+
+	server := httptest.NewServer(superRouter)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "users" {
+		t.Fatalf("response = %d %q, want 200 %q", resp.StatusCode, body, "users")
+	}
+}
+
+func TestStaticFiles(t *testing.T) {
+	dir, err := os.MkdirTemp("", "supermuxer-static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "css"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "css", "app.css"), []byte("body { margin: 0 }"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	New(mux).SubGroup("/public").StaticFiles("/assets/", dir)
+
+	rec := serve(mux, http.MethodGet, "/public/assets/css/app.css")
+	if rec.Code != http.StatusOK || rec.Body.String() != "body { margin: 0 }" {
+		t.Fatalf("response = %d %q, want 200 with the file", rec.Code, rec.Body.String())
+	}
+
+	if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/css") {
+		t.Fatalf("Content-Type = %q, want text/css", contentType)
+	}
+
+	if rec := serve(mux, http.MethodGet, "/public/assets/missing.css"); rec.Code != http.StatusNotFound {
+		t.Fatalf("status of a missing file = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestWithAutoHEAD(t *testing.T) {
+	mux := http.NewServeMux()
+	superRouter := New(mux).WithAutoHEAD(true)
+	superRouter.Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "2")
+		w.Write([]byte("<html><body>users</body></html>"))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	get, err := server.Client().Get(server.URL + "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	get.Body.Close()
+
+	head, err := server.Client().Head(server.URL + "/users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer head.Body.Close()
+
+	if body, _ := io.ReadAll(head.Body); head.StatusCode != get.StatusCode || len(body) != 0 {
+		t.Fatalf("HEAD response = %d %q, want %d without a body", head.StatusCode, body, get.StatusCode)
+	}
+
+	for _, name := range []string{"X-Total-Count", "Content-Type", "Content-Length"} {
+		if head.Header.Get(name) != get.Header.Get(name) {
+			t.Errorf("HEAD %s = %q, want %q", name, head.Header.Get(name), get.Header.Get(name))
+		}
+	}
+
+	if _, pattern := mux.Handler(httptest.NewRequest(http.MethodHead, "/users", nil)); pattern != "HEAD /users" {
+		t.Fatalf("HEAD pattern = %q, want %q", pattern, "HEAD /users")
+	}
+
+	superRouter.WithAutoHEAD(false).Get("/orders", textHandler("orders"))
+	if _, pattern := mux.Handler(httptest.NewRequest(http.MethodHead, "/orders", nil)); pattern != "GET /orders" {
+		t.Fatalf("HEAD pattern without the option = %q, want %q", pattern, "GET /orders")
+	}
+}
+
+func TestRedirect(t *testing.T) {
+	mux := http.NewServeMux()
+	New(mux).Redirect("/v1/users", "/v2/users", http.StatusPermanentRedirect).Redirect("/old", "https://example.com/new", http.StatusFound)
+
+	tests := []struct {
+		method   string
+		target   string
+		code     int
+		location string
+	}{
+		{method: http.MethodGet, target: "/v1/users", code: http.StatusPermanentRedirect, location: "/v2/users"},
+		{method: http.MethodPost, target: "/v1/users", code: http.StatusPermanentRedirect, location: "/v2/users"},
+		{method: http.MethodGet, target: "/old", code: http.StatusFound, location: "https://example.com/new"},
+	}
+
+	for _, tt := range tests {
+		rec := serve(mux, tt.method, tt.target)
+		if rec.Code != tt.code || rec.Header().Get("Location") != tt.location {
+			t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.target, rec.Code, rec.Header().Get("Location"), tt.code, tt.location)
+		}
+	}
+
+	assertPanics(t, "invalid redirect code 200", func() {
+		New(http.NewServeMux()).Redirect("/from", "/to", http.StatusOK)
+	})
+}
+
+func TestClone(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	original := New(mux).SubGroup("/api").AddMiddlewares(recordingMiddleware("original", &calls))
+
+	clone := original.Clone()
+	clone.AddMiddlewares(recordingMiddleware("clone", &calls)).Get("/test", textHandler("test"))
+	if _, err := clone.ReplaceMiddlewareAt(0, recordingMiddleware("replaced", &calls)); err != nil {
+		t.Fatal(err)
+	}
+
+	original.Get("/users", textHandler("users"))
+
+	serve(mux, http.MethodGet, "/api/users")
+	if want := []string{"original"}; !slices.Equal(calls, want) {
+		t.Fatalf("original calls = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	serve(mux, http.MethodGet, "/api/test")
+	if want := []string{"original", "clone"}; !slices.Equal(calls, want) {
+		t.Fatalf("clone calls = %v, want %v", calls, want)
+	}
+
+	if len(original.middlewares) != 1 {
+		t.Fatalf("original middlewares = %d, want 1", len(original.middlewares))
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux).AddMiddlewares(recordingMiddleware("middleware1", &calls), recordingMiddleware("middleware2", &calls))
+
+	middlewares := superRouter.Middlewares()
+	if len(middlewares) != 2 {
+		t.Fatalf("middlewares = %d, want 2", len(middlewares))
+	}
+
+	middlewares[0] = recordingMiddleware("changed", &calls)
+	middlewares = append(middlewares, recordingMiddleware("appended", &calls))
+
+	superRouter.Get("/users", textHandler("users"))
+	serve(mux, http.MethodGet, "/users")
+
+	if want := []string{"middleware1", "middleware2"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestInsertMiddlewareAt(t *testing.T) {
+	tests := []struct {
+		index int
+		want  []string
+	}{
+		{index: 0, want: []string{"inserted", "middleware1", "middleware2"}},
+		{index: 1, want: []string{"middleware1", "inserted", "middleware2"}},
+		{index: 2, want: []string{"middleware1", "middleware2", "inserted"}},
+	}
+
+	for _, tt := range tests {
+		var calls []string
+		mux := http.NewServeMux()
+		superRouter := New(mux).AddMiddlewares(recordingMiddleware("middleware1", &calls), recordingMiddleware("middleware2", &calls))
+
+		if _, err := superRouter.InsertMiddlewareAt(tt.index, recordingMiddleware("inserted", &calls)); err != nil {
+			t.Fatal(err)
+		}
+
+		superRouter.Get("/users", textHandler("users"))
+		serve(mux, http.MethodGet, "/users")
+
+		if !slices.Equal(calls, tt.want) {
+			t.Errorf("InsertMiddlewareAt(%d) calls = %v, want %v", tt.index, calls, tt.want)
+		}
+	}
+
+	superRouter := New(http.NewServeMux())
+	for _, index := range []int{-1, 1} {
+		if _, err := superRouter.InsertMiddlewareAt(index, recordingMiddleware("inserted", new([]string))); err == nil {
+			t.Errorf("InsertMiddlewareAt(%d) error = nil, want an error", index)
+		}
+	}
+}
+
+func TestConditionalMiddleware(t *testing.T) {
+	var calls []string
+	isExternal := func(r *http.Request) bool { return r.Header.Get("X-Internal") == "" }
+
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(ConditionalMiddleware(isExternal, recordingMiddleware("auth", &calls))).Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "handler")
+	})
+
+	serve(mux, http.MethodGet, "/users")
+	if want := []string{"auth", "handler"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls of a matching request = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("X-Internal", "true")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if want := []string{"handler"}; !slices.Equal(calls, want) {
+		t.Fatalf("calls of a non-matching request = %v, want %v", calls, want)
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	t.Run("AutoHEAD", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{AutoHEAD: true}).Get("/users", textHandler("users"))
+
+		if _, pattern := mux.Handler(httptest.NewRequest(http.MethodHead, "/users", nil)); pattern != "HEAD /users" {
+			t.Fatalf("HEAD pattern = %q, want %q", pattern, "HEAD /users")
+		}
+	})
+
+	t.Run("TrailingSlashRedirect", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{TrailingSlashRedirect: true}).Get("/users", textHandler("users"))
+
+		if rec := serve(mux, http.MethodGet, "/users/"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/users" {
+			t.Fatalf("response = %d %q, want 301 to /users", rec.Code, rec.Header().Get("Location"))
+		}
+	})
+
+	t.Run("NotFoundHandler", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{NotFoundHandler: textHandler("custom not found")}).Get("/users", textHandler("users"))
+
+		if rec := serve(mux, http.MethodGet, "/unknown"); rec.Body.String() != "custom not found" {
+			t.Fatalf("body = %q, want %q", rec.Body.String(), "custom not found")
+		}
+	})
+
+	t.Run("MethodNotAllowedHandler", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{MethodNotAllowedHandler: textHandler("custom method not allowed")}).Get("/users", textHandler("users"))
+
+		if rec := serve(mux, http.MethodPost, "/users"); rec.Body.String() != "custom method not allowed" {
+			t.Fatalf("body = %q, want %q", rec.Body.String(), "custom method not allowed")
+		}
+	})
+
+	t.Run("PanicHandler", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{PanicHandler: func(w http.ResponseWriter, r *http.Request, v any) {
+			http.Error(w, fmt.Sprint(v), http.StatusInternalServerError)
+		}}).Get("/users", func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+
+		if rec := serve(mux, http.MethodGet, "/users"); rec.Code != http.StatusInternalServerError || rec.Body.String() != "boom\n" {
+			t.Fatalf("response = %d %q, want 500 %q", rec.Code, rec.Body.String(), "boom\n")
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{}).Get("/users", textHandler("users"))
+
+		if _, pattern := mux.Handler(httptest.NewRequest(http.MethodPost, "/users", nil)); pattern != "" {
+			t.Fatalf("fallback pattern = %q, want none", pattern)
+		}
+	})
+}
+
+func TestDuplicatedRoutes(t *testing.T) {
+	superRouter := New(http.NewServeMux())
+	superRouter.SubGroup("/api").Get("/users", textHandler("users"))
+
+	assertPanics(t, `route "GET /api/users" is already registered`, func() {
+		superRouter.Group("/api").Get("/users", textHandler("users"))
+	})
+
+	superRouter.SubGroup("/api").Post("/users", textHandler("created"))
+	superRouter.SubGroup("").WithHost("api.example.com").Get("/api/users", textHandler("users"))
+}
+
+func TestWalk(t *testing.T) {
+	superRouter := New(http.NewServeMux())
+	superRouter.Get("/health", textHandler("ok"))
+	superRouter.AddMiddlewares(recordingMiddleware("middleware1", new([]string))).SubGroup("/users").
+		AddMiddlewares(recordingMiddleware("middleware2", new([]string))).Post("/{id}", textHandler("updated"))
+
+	var walked []string
+	err := superRouter.Walk(func(method string, fullPattern string, middlewareCount int) error {
+		walked = append(walked, fmt.Sprintf("%s|%s|%d", method, fullPattern, middlewareCount))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"GET|GET /health|0", "POST|POST /users/{id}|2"}; !slices.Equal(walked, want) {
+		t.Fatalf("walked = %v, want %v", walked, want)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = superRouter.Walk(func(method string, fullPattern string, middlewareCount int) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Fatalf("error = %v after %d calls, want the callback error after 1 call", err, calls)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	api := New(mux).SubGroup("/api").AddMiddlewares(recordingMiddleware("api", &calls))
+	api.Version("1").Get("/endpoint", textHandler("v1"))
+	api.Version("2").Get("/endpoint", textHandler("v2"))
+
+	for _, version := range []string{"v1", "v2"} {
+		calls = nil
+		if rec := serve(mux, http.MethodGet, "/api/"+version+"/endpoint"); rec.Body.String() != version {
+			t.Errorf("body = %q, want %q", rec.Body.String(), version)
+		}
+
+		if want := []string{"api"}; !slices.Equal(calls, want) {
+			t.Errorf("%s calls = %v, want %v", version, calls, want)
+		}
+	}
+}
+
+func TestRoutePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "GET /users/{id}", want: "/users/{id}"},
+		{pattern: "GET api.example.com/users/{id}", want: "/users/{id}"},
+		{pattern: "api.example.com/health", want: "/health"},
+		{pattern: "/static/", want: "/static/"},
+		{pattern: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Pattern = tt.pattern
+
+			if got := RoutePattern(req); got != tt.want {
+				t.Fatalf("RoutePattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("matched route", func(t *testing.T) {
+		mux := http.NewServeMux()
+		New(mux).SubGroup("/api").Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(RoutePattern(r)))
+		})
+
+		if rec := serve(mux, http.MethodGet, "/api/users/7"); rec.Body.String() != "/api/users/{id}" {
+			t.Fatalf("RoutePattern() = %q, want %q", rec.Body.String(), "/api/users/{id}")
+		}
+	})
+}
+
+func TestAddGlobalMiddlewares(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	superRouter := New(mux)
+	superRouter.Get("/before", textHandler("before"))
+
+	users := superRouter.SubGroup("/users").AddMiddlewares(recordingMiddleware("users", &calls))
+	superRouter.AddGlobalMiddlewares(recordingMiddleware("global1", &calls), recordingMiddleware("global2", &calls))
+
+	users.Get("/{id}", textHandler("user"))
+	superRouter.GroupWith("/admin", recordingMiddleware("admin", &calls)).Get("/stats", textHandler("stats"))
+	superRouter.NotFound(textHandler("not found"))
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{target: "/users/7", want: []string{"global1", "global2", "users"}},
+		{target: "/admin/stats", want: []string{"global1", "global2", "admin"}},
+		{target: "/missing", want: []string{"global1", "global2"}},
+		{target: "/before", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			calls = nil
+			serve(mux, http.MethodGet, tt.target)
+
+			if !slices.Equal(calls, tt.want) {
+				t.Fatalf("calls = %v, want %v", calls, tt.want)
+			}
+		})
+	}
+
+	t.Run("router option", func(t *testing.T) {
+		calls = nil
+		mux := http.NewServeMux()
+		NewWithOptions(mux, RouterOptions{GlobalMiddlewares: []MiddlewareFunc{recordingMiddleware("global", &calls)}}).
+			GroupWith("/api", recordingMiddleware("api", &calls)).Get("/users", textHandler("users"))
+
+		serve(mux, http.MethodGet, "/api/users")
+		if want := []string{"global", "api"}; !slices.Equal(calls, want) {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	})
+
+	t.Run("mounted router", func(t *testing.T) {
+		calls = nil
+		sub := New(http.NewServeMux()).AddGlobalMiddlewares(recordingMiddleware("sub global", &calls))
+		sub.AddMiddlewares(recordingMiddleware("sub", &calls)).Get("/orders", textHandler("orders"))
+
+		mux := http.NewServeMux()
+		parent := New(mux).AddGlobalMiddlewares(recordingMiddleware("global", &calls))
+		parent.AddMiddlewares(recordingMiddleware("parent", &calls)).Mount("/shop", sub)
+
+		serve(mux, http.MethodGet, "/shop/orders")
+		if want := []string{"global", "parent", "sub global", "sub"}; !slices.Equal(calls, want) {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+// Package supermuxertest provides utilities to test the routes and middlewares of a supermuxer router,
+// like net/http/httptest does for the http.Handler.
+package supermuxertest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dbarbosadev/supermuxer"
+)
+
+// TestRouter is a supermuxer router served by an httptest.Server for the duration of a test.
+type TestRouter struct {
+	t      *testing.T
+	router supermuxer.Router
+	server *httptest.Server
+}
+
+// NewTestRouter creates a router on a new http.ServeMux, served by an httptest.Server that is closed when the test
+// and its subtests complete. The routes and middlewares can be added to Router after the server is started.
+//
+// Example:
+//
+//	func TestGetUser(t *testing.T) {
+//		tr := supermuxertest.NewTestRouter(t)
+//		tr.Router().AddMiddlewares(authMiddleware).Get("/users/{id}", handler)
+//
+//		resp := tr.Get("/users/42")
+//		if resp.StatusCode != http.StatusOK {
+//			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+//		}
+//	}
+func NewTestRouter(t *testing.T) *TestRouter {
+	t.Helper()
+
+	router := supermuxer.New(http.NewServeMux())
+	tr := &TestRouter{t: t, router: router, server: httptest.NewServer(router)}
+	t.Cleanup(tr.Close)
+
+	return tr
+}
+
+// Router returns the router served by the test server.
+func (tr *TestRouter) Router() supermuxer.Router {
+	return tr.router
+}
+
+// Client returns an http.Client configured for the test server.
+func (tr *TestRouter) Client() *http.Client {
+	return tr.server.Client()
+}
+
+// URL returns the base URL of the test server, e.g. 'http://127.0.0.1:41234'.
+func (tr *TestRouter) URL() string {
+	return tr.server.URL
+}
+
+// Close shuts down the test server. It is called when the test completes, so it is only needed to stop it earlier.
+func (tr *TestRouter) Close() {
+	tr.server.Close()
+}
+
+// Get sends a 'GET' request for the path to the test server, failing the test on transport errors.
+// The response body is closed when the test completes.
+func (tr *TestRouter) Get(path string) *http.Response {
+	tr.t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, tr.server.URL+path, nil)
+	if err != nil {
+		tr.t.Fatalf("supermuxertest: creating request: %v", err)
+	}
+
+	return tr.Do(req)
+}
+
+// Post sends a 'POST' request for the path with the content type and body to the test server, failing the test
+// on transport errors. The response body is closed when the test completes.
+func (tr *TestRouter) Post(path string, contentType string, body io.Reader) *http.Response {
+	tr.t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, tr.server.URL+path, body)
+	if err != nil {
+		tr.t.Fatalf("supermuxertest: creating request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	return tr.Do(req)
+}
+
+// Do sends the request, whose URL must point to the test server, failing the test on transport errors.
+// The response body is closed when the test completes.
+func (tr *TestRouter) Do(req *http.Request) *http.Response {
+	tr.t.Helper()
+
+	resp, err := tr.Client().Do(req)
+	if err != nil {
+		tr.t.Fatalf("supermuxertest: sending %s %s: %v", req.Method, req.URL, err)
+	}
+	tr.t.Cleanup(func() { resp.Body.Close() })
+
+	return resp
+}
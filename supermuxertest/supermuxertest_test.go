@@ -0,0 +1,96 @@
+package supermuxertest
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dbarbosadev/supermuxer"
+)
+
+func TestTestRouter(t *testing.T) {
+	tr := NewTestRouter(t)
+
+	header := func(name, value string) supermuxer.MiddlewareFunc {
+		return func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(name, value)
+				next(w, r)
+			}
+		}
+	}
+
+	// The routes are added after the server is started.
+	tr.Router().AddMiddlewares(header("X-Middleware", "applied")).
+		Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("user " + r.PathValue("id")))
+		}).
+		Post("/users", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(r.Header.Get("Content-Type") + " " + string(body)))
+		}).
+		Delete("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+	readBody := func(t *testing.T, resp *http.Response) string {
+		t.Helper()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("reading body: %v", err)
+		}
+
+		return string(body)
+	}
+
+	t.Run("get", func(t *testing.T) {
+		resp := tr.Get("/users/42")
+
+		if resp.StatusCode != http.StatusOK || readBody(t, resp) != "user 42" {
+			t.Fatalf("response = %d, want %d with %q", resp.StatusCode, http.StatusOK, "user 42")
+		}
+
+		if got := resp.Header.Get("X-Middleware"); got != "applied" {
+			t.Fatalf("X-Middleware = %q, want %q", got, "applied")
+		}
+	})
+
+	t.Run("post", func(t *testing.T) {
+		resp := tr.Post("/users", "application/json", strings.NewReader(`{"name":"Ada"}`))
+
+		if got, want := readBody(t, resp), `application/json {"name":"Ada"}`; resp.StatusCode != http.StatusCreated || got != want {
+			t.Fatalf("response = %d %q, want %d %q", resp.StatusCode, got, http.StatusCreated, want)
+		}
+	})
+
+	t.Run("do", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodDelete, tr.URL()+"/users/42", nil)
+
+		if resp := tr.Do(req); resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+		}
+	})
+
+	t.Run("unregistered route", func(t *testing.T) {
+		if resp := tr.Get("/orders"); resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+		}
+	})
+
+	t.Run("closed by the cleanup", func(t *testing.T) {
+		var closed *TestRouter
+		t.Run("subtest", func(t *testing.T) {
+			closed = NewTestRouter(t)
+			closed.Router().Get("/", func(w http.ResponseWriter, r *http.Request) {})
+			closed.Get("/")
+		})
+
+		if resp, err := http.Get(closed.URL()); err == nil {
+			resp.Body.Close()
+			t.Fatal("request to the server after the test completed succeeded, want an error")
+		}
+	})
+}
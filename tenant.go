@@ -0,0 +1,49 @@
+package supermuxer
+
+import (
+	"context"
+	"net/http"
+)
+
+type tenantContextKey struct{}
+
+// NewMultiTenantMiddleware creates a middleware that identifies the tenant of every request with the extractor,
+// e.g. from the subdomain, a header or a JWT claim, storing it in the request context, readable with
+// TenantFromContext. Requests for which the extractor returns an error get 401.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	tenants := supermuxer.NewMultiTenantMiddleware(func(r *http.Request) (string, error) {
+//		tenant, _, ok := strings.Cut(r.Host, ".")
+//		if !ok {
+//			return "", errors.New("missing tenant subdomain")
+//		}
+//		return tenant, nil
+//	})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(tenants).Get("/invoices", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /invoices'
+//		with the 'acme' tenant for the host 'acme.example.com'
+func NewMultiTenantMiddleware(extractor func(*http.Request) (string, error)) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			tenant, err := extractor(r)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), tenantContextKey{}, tenant)))
+		}
+	}
+}
+
+// TenantFromContext returns the tenant stored by the middleware created by NewMultiTenantMiddleware.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenant, ok
+}
@@ -0,0 +1,49 @@
+package supermuxer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMultiTenantMiddleware(t *testing.T) {
+	subdomain := func(r *http.Request) (string, error) {
+		tenant, _, ok := strings.Cut(r.Host, ".")
+		if !ok || tenant == "" {
+			return "", errors.New("missing tenant subdomain")
+		}
+
+		return tenant, nil
+	}
+
+	mux := http.NewServeMux()
+	New(mux).AddMiddlewares(NewMultiTenantMiddleware(subdomain)).Get("/users", func(w http.ResponseWriter, r *http.Request) {
+		tenant, ok := TenantFromContext(r.Context())
+		if !ok {
+			t.Fatal("TenantFromContext() ok = false, want true")
+		}
+		w.Write([]byte(tenant))
+	})
+
+	t.Run("extracted tenant", func(t *testing.T) {
+		rec := serve(mux, http.MethodGet, "http://acme.example.com/users")
+		if rec.Code != http.StatusOK || rec.Body.String() != "acme" {
+			t.Fatalf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), http.StatusOK, "acme")
+		}
+	})
+
+	t.Run("extraction failure", func(t *testing.T) {
+		rec := serve(mux, http.MethodGet, "http://localhost/users")
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("without middleware", func(t *testing.T) {
+		if _, ok := TenantFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+			t.Fatal("TenantFromContext() ok = true, want false")
+		}
+	})
+}
@@ -0,0 +1,41 @@
+package supermuxer
+
+import (
+	"net/http"
+)
+
+// NewThrottleMiddleware creates a middleware that lets at most maxConcurrent requests run the next handler at once.
+// The requests beyond it get 503 with the 'Retry-After: 1' header right away. It panics if maxConcurrent is not positive.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewThrottleMiddleware(10)).Post("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /reports'
+//		with at most 10 requests at once, responding with 503 to the others
+func NewThrottleMiddleware(maxConcurrent int) MiddlewareFunc {
+	if maxConcurrent <= 0 {
+		panic("supermuxer: throttle maximum of concurrent requests must be positive")
+	}
+
+	semaphore := make(chan struct{}, maxConcurrent)
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case semaphore <- struct{}{}:
+			default:
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			defer func() { <-semaphore }()
+
+			next(w, r)
+		}
+	}
+}
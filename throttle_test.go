@@ -0,0 +1,69 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestThrottleMiddleware(t *testing.T) {
+	const maxConcurrent, requests = 3, 10
+
+	var arrived sync.WaitGroup
+	arrived.Add(maxConcurrent)
+	release := make(chan struct{})
+
+	handler := NewThrottleMiddleware(maxConcurrent)(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	results := make(chan *httptest.ResponseRecorder, requests)
+	send := func() {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/reports", nil))
+		results <- rec
+	}
+
+	for range maxConcurrent {
+		go send()
+	}
+	arrived.Wait()
+
+	// The permitted requests are all running, so the excess ones are rejected right away.
+	for range requests - maxConcurrent {
+		go send()
+	}
+
+	for range requests - maxConcurrent {
+		rec := <-results
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("excess status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+
+		if rec.Header().Get("Retry-After") != "1" {
+			t.Fatalf("Retry-After = %q, want %q", rec.Header().Get("Retry-After"), "1")
+		}
+	}
+
+	close(release)
+	for range maxConcurrent {
+		if rec := <-results; rec.Code != http.StatusOK {
+			t.Fatalf("permitted status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	}
+
+	// The slots are released once the requests complete.
+	arrived.Add(1)
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/reports", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status after the release = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	assertPanics(t, "throttle maximum of concurrent requests must be positive", func() {
+		NewThrottleMiddleware(0)
+	})
+}
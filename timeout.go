@@ -0,0 +1,122 @@
+package supermuxer
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// timeoutWriter buffers the response of a handler running in its own goroutine,
+// discarding its writes once the request timed out.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut || w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.status = code
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = http.StatusOK
+	}
+
+	return w.buf.Write(b)
+}
+
+// NewTimeoutMiddleware creates a middleware that cancels the request context after the duration.
+// The next handler runs in its own goroutine with a buffered response, which is sent once the handler returns.
+// When the deadline is exceeded first, the response is 503 with the 'Retry-After: 1' header, and the later
+// writes of the handler are discarded with http.ErrHandlerTimeout.
+// Handlers should return when the request context is done, so their goroutine does not keep running.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewTimeoutMiddleware(5 * time.Second))
+//	superRouter.Get("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /reports'
+//		responding with 503 if the handler takes longer than 5 seconds
+func NewTimeoutMiddleware(d time.Duration) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{header: http.Header{}}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+
+			go func() {
+				defer func() {
+					if v := recover(); v != nil {
+						panicked <- v
+					}
+				}()
+
+				next(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case v := <-panicked:
+				panic(v)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				header := w.Header()
+				for key, values := range tw.header {
+					header[key] = values
+				}
+
+				if !tw.wroteHeader {
+					tw.status = http.StatusOK
+				}
+
+				w.WriteHeader(tw.status)
+				w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+
+				tw.timedOut = true
+				tw.buf.Reset()
+
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			}
+		}
+	}
+}
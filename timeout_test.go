@@ -0,0 +1,71 @@
+package supermuxer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Run("fast handler", func(t *testing.T) {
+		handler := NewTimeoutMiddleware(time.Second)(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Handler", "done")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte("created"))
+		})
+
+		rec := serve(handler, http.MethodPost, "/")
+		if rec.Code != http.StatusCreated || rec.Body.String() != "created" || rec.Header().Get("X-Handler") != "done" {
+			t.Fatalf("response = %d %q %v, want the handler response", rec.Code, rec.Body.String(), rec.Header())
+		}
+	})
+
+	t.Run("slow handler", func(t *testing.T) {
+		release := make(chan struct{})
+		result := make(chan error, 1)
+		canceled := make(chan error, 1)
+
+		handler := NewTimeoutMiddleware(20 * time.Millisecond)(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+			canceled <- r.Context().Err()
+
+			// The handler ignores the cancellation and keeps writing.
+			<-release
+			_, err := w.Write([]byte("late"))
+			result <- err
+		})
+
+		start := time.Now()
+		rec := serve(handler, http.MethodGet, "/")
+		elapsed := time.Since(start)
+		close(release)
+
+		if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "1" {
+			t.Fatalf("response = %d %v, want 503 with 'Retry-After: 1'", rec.Code, rec.Header())
+		}
+
+		if elapsed > time.Second {
+			t.Fatalf("middleware returned after %s, want it not to wait for the handler", elapsed)
+		}
+
+		if err := <-canceled; !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("context error = %v, want context.DeadlineExceeded", err)
+		}
+
+		if err := <-result; !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Fatalf("late write error = %v, want http.ErrHandlerTimeout", err)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		handler := NewTimeoutMiddleware(time.Second)(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})
+
+		assertPanics(t, "boom", func() {
+			serve(handler, http.MethodGet, "/")
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package supermuxer
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// timingWriter sets the response time header right before the headers are written,
+// as they cannot be changed afterwards.
+type timingWriter struct {
+	http.ResponseWriter
+	header      string
+	start       time.Time
+	wroteHeader bool
+}
+
+func (w *timingWriter) setHeader() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	w.Header().Set(w.header, strconv.FormatFloat(durationMs(time.Since(w.start)), 'f', 3, 64)+"ms")
+}
+
+func (w *timingWriter) WriteHeader(code int) {
+	w.setHeader()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timingWriter) Write(b []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *timingWriter) Flush() {
+	w.setHeader()
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *timingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewResponseTimingMiddleware creates a middleware that sets the header, commonly 'X-Response-Time', to the time
+// the handler took to respond in milliseconds, e.g. '12.345ms'. As headers cannot change once written,
+// the time is measured when the handler writes the headers, or when it returns or panics without writing them.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewResponseTimingMiddleware("X-Response-Time"))
+//	superRouter.Get("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /users' with the 'X-Response-Time' header
+func NewResponseTimingMiddleware(header string) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			tw := &timingWriter{ResponseWriter: w, header: header, start: time.Now()}
+			defer tw.setHeader()
+
+			next(tw, r)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package supermuxer
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResponseTimingMiddleware(t *testing.T) {
+	parse := func(t *testing.T, value string) time.Duration {
+		t.Helper()
+
+		ms, ok := strings.CutSuffix(value, "ms")
+		if !ok {
+			t.Fatalf("header = %q, want a duration in milliseconds", value)
+		}
+
+		parsed, err := strconv.ParseFloat(ms, 64)
+		if err != nil || parsed < 0 {
+			t.Fatalf("header = %q, want a non-negative duration", value)
+		}
+
+		return time.Duration(parsed * float64(time.Millisecond))
+	}
+
+	t.Run("written response", func(t *testing.T) {
+		handler := NewResponseTimingMiddleware("X-Response-Time")(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(5 * time.Millisecond)
+			w.Write([]byte("users"))
+		})
+
+		rec := serve(handler, http.MethodGet, "/users")
+		if duration := parse(t, rec.Header().Get("X-Response-Time")); duration < 5*time.Millisecond {
+			t.Fatalf("duration = %s, want at least 5ms", duration)
+		}
+	})
+
+	t.Run("empty response", func(t *testing.T) {
+		handler := NewResponseTimingMiddleware("Server-Timing-Total")(func(w http.ResponseWriter, r *http.Request) {})
+
+		rec := serve(handler, http.MethodGet, "/users")
+		parse(t, rec.Header().Get("Server-Timing-Total"))
+	})
+}
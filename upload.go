@@ -0,0 +1,84 @@
+package supermuxer
+
+import (
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+)
+
+// uploadFormOverhead is the number of bytes allowed on top of the files of a form, for its fields and part headers.
+const uploadFormOverhead = 1 << 20
+
+// UploadConfig configures the uploads handled by ServeUpload. Zero values disable the limits.
+type UploadConfig struct {
+	// MaxFileSize is the maximum size, in bytes, of each file. Bigger files get 413.
+	MaxFileSize int64
+	// MaxFiles is the maximum number of files of the form. More files get 400.
+	MaxFiles int
+	// AllowedMIMETypes lists the accepted media types, declared by the 'Content-Type' of each file.
+	// Other types get 415.
+	AllowedMIMETypes []string
+	// MaxMemory is the number of bytes of the form kept in memory, the rest is stored in temporary files.
+	// Defaults to 32 MiB.
+	MaxMemory int64
+}
+
+func (r *router) ServeUpload(path string, cfg UploadConfig, handler func(http.ResponseWriter, *http.Request, []*multipart.FileHeader)) *router {
+	if cfg.MaxMemory <= 0 {
+		cfg.MaxMemory = 32 << 20
+	}
+
+	return r.Post(path, func(w http.ResponseWriter, req *http.Request) {
+		if cfg.MaxFiles > 0 && cfg.MaxFileSize > 0 {
+			req.Body = http.MaxBytesReader(w, req.Body, int64(cfg.MaxFiles)*cfg.MaxFileSize+uploadFormOverhead)
+		}
+
+		err := req.ParseMultipartForm(cfg.MaxMemory)
+		if req.MultipartForm != nil {
+			defer req.MultipartForm.RemoveAll()
+		}
+
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			switch {
+			case errors.Is(err, http.ErrNotMultipart):
+				http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+			case errors.As(err, &maxBytesErr):
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			default:
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			}
+
+			return
+		}
+
+		files := []*multipart.FileHeader{}
+		for _, headers := range req.MultipartForm.File {
+			files = append(files, headers...)
+		}
+
+		if cfg.MaxFiles > 0 && len(files) > cfg.MaxFiles {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		for _, file := range files {
+			if cfg.MaxFileSize > 0 && file.Size > cfg.MaxFileSize {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			if cfg.AllowedMIMETypes != nil {
+				mediaType, _, _ := mime.ParseMediaType(file.Header.Get("Content-Type"))
+				if !slices.Contains(cfg.AllowedMIMETypes, mediaType) {
+					http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+					return
+				}
+			}
+		}
+
+		handler(w, req, files)
+	})
+}
@@ -0,0 +1,143 @@
+package supermuxer
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+)
+
+type uploadFile struct {
+	name        string
+	contentType string
+	content     string
+}
+
+// newUploadRequest creates a multipart request with the files in the 'files' field.
+func newUploadRequest(t *testing.T, files ...uploadFile) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for _, file := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="files"; filename="`+file.name+`"`)
+		header.Set("Content-Type", file.contentType)
+
+		part, err := form.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(file.content))
+	}
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/avatars", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestServeUpload(t *testing.T) {
+	cfg := UploadConfig{MaxFileSize: 16, MaxFiles: 2, AllowedMIMETypes: []string{"image/png", "image/jpeg"}}
+
+	newMux := func(cfg UploadConfig, got *[]string) *http.ServeMux {
+		mux := http.NewServeMux()
+		New(mux).ServeUpload("/avatars", cfg, func(w http.ResponseWriter, r *http.Request, files []*multipart.FileHeader) {
+			for _, header := range files {
+				file, err := header.Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				content, _ := io.ReadAll(file)
+				file.Close()
+				*got = append(*got, header.Filename+":"+string(content))
+			}
+			w.WriteHeader(http.StatusCreated)
+		})
+
+		return mux
+	}
+
+	t.Run("accepted files", func(t *testing.T) {
+		var got []string
+		rec := httptest.NewRecorder()
+		newMux(cfg, &got).ServeHTTP(rec, newUploadRequest(t,
+			uploadFile{name: "a.png", contentType: "image/png", content: "png"},
+			uploadFile{name: "b.jpg", contentType: "image/jpeg; charset=binary", content: "jpeg"},
+		))
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusCreated)
+		}
+
+		if strings.Join(got, ",") != "a.png:png,b.jpg:jpeg" {
+			t.Fatalf("files = %v, want a.png and b.jpg", got)
+		}
+	})
+
+	rejected := []struct {
+		name   string
+		req    func(t *testing.T) *http.Request
+		status int
+	}{
+		{name: "disallowed media type", status: http.StatusUnsupportedMediaType, req: func(t *testing.T) *http.Request {
+			return newUploadRequest(t, uploadFile{name: "a.exe", contentType: "application/octet-stream", content: "exe"})
+		}},
+		{name: "not multipart", status: http.StatusUnsupportedMediaType, req: func(t *testing.T) *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/avatars", strings.NewReader(`{"avatar":"png"}`))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		}},
+		{name: "too many files", status: http.StatusBadRequest, req: func(t *testing.T) *http.Request {
+			file := uploadFile{name: "a.png", contentType: "image/png", content: "png"}
+			return newUploadRequest(t, file, file, file)
+		}},
+		{name: "file too large", status: http.StatusRequestEntityTooLarge, req: func(t *testing.T) *http.Request {
+			return newUploadRequest(t, uploadFile{name: "a.png", contentType: "image/png", content: strings.Repeat("x", 17)})
+		}},
+		{name: "body too large", status: http.StatusRequestEntityTooLarge, req: func(t *testing.T) *http.Request {
+			return newUploadRequest(t, uploadFile{name: "a.png", contentType: "image/png", content: strings.Repeat("x", uploadFormOverhead+64)})
+		}},
+	}
+
+	for _, tt := range rejected {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			rec := httptest.NewRecorder()
+			newMux(cfg, &got).ServeHTTP(rec, tt.req(t))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+
+			if got != nil {
+				t.Fatalf("handler received %v, want no call", got)
+			}
+		})
+	}
+
+	t.Run("temporary files are removed", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("TMPDIR", dir)
+
+		var got []string
+		rec := httptest.NewRecorder()
+		newMux(UploadConfig{MaxMemory: 1}, &got).ServeHTTP(rec, newUploadRequest(t,
+			uploadFile{name: "a.png", contentType: "image/png", content: strings.Repeat("x", 1024)},
+		))
+
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusCreated)
+		}
+
+		if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+			t.Fatalf("temporary files = %d, want 0", len(entries))
+		}
+	})
+}
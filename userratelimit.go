@@ -0,0 +1,93 @@
+package supermuxer
+
+import (
+	"math"
+	"net/http"
+)
+
+// UserRateLimitConfig configures the middleware created by NewRateLimitByUserMiddleware.
+type UserRateLimitConfig struct {
+	// DefaultRPS is the rate at which the users of the tiers without a limit regain requests.
+	DefaultRPS float64
+	// TierFunc returns the tier of the user, e.g. from the JWT claims. Defaults to every user having the default limit.
+	TierFunc func(*http.Request) string
+	// TierLimits maps the tiers to the rate at which their users regain requests. math.Inf(1) disables the limit.
+	TierLimits map[string]float64
+	// Burst is the number of requests a user can make at once. Defaults to 1.
+	Burst int
+	// KeyFunc identifies the user. Defaults to the 'sub' claim stored by the middleware created by
+	// NewJWTMiddleware under the default key, falling back to the client IP.
+	KeyFunc func(*http.Request) string
+}
+
+// NewRateLimitByUserMiddleware creates a middleware that limits the requests of each user, like the middleware created
+// by NewRateLimitMiddleware, at the rate of the tier of the user, so e.g. the free tier gets fewer requests than the
+// paid one. The requests over the limit get 429 with the 'Retry-After' header. The buckets are kept in memory for
+// the users and tiers seen, and removed once they refill, so a user whose tier changes starts with a full bucket
+// of the new tier. The buckets are the token buckets of NewRateLimitMiddleware, not golang.org/x/time/rate
+// limiters, so the module still only depends on the standard library.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	limiter := supermuxer.NewRateLimitByUserMiddleware(supermuxer.UserRateLimitConfig{
+//		DefaultRPS: 1,
+//		TierFunc:   planFromJWTClaims,
+//		TierLimits: map[string]float64{"pro": 50, "internal": math.Inf(1)},
+//		Burst:      10,
+//	})
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(jwt, limiter).Get("/reports", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /reports'
+//		limiting the 'pro' users to 50 requests per second, the 'internal' users to none and the others to 1
+func NewRateLimitByUserMiddleware(cfg UserRateLimitConfig) MiddlewareFunc {
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = jwtSubjectOrClientIP
+	}
+
+	buckets := newTokenBucketStore()
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			tier := ""
+			if cfg.TierFunc != nil {
+				tier = cfg.TierFunc(r)
+			}
+
+			rps, ok := cfg.TierLimits[tier]
+			if !ok {
+				rps = cfg.DefaultRPS
+			}
+
+			if math.IsInf(rps, 1) {
+				next(w, r)
+				return
+			}
+
+			// The tier is part of the key, so the bucket of a user always has the rate of its current tier.
+			key := tier + "\x00" + cfg.KeyFunc(r)
+
+			if allowed, wait := buckets.get(key, rps, cfg.Burst).allow(); !allowed {
+				w.Header().Set("Retry-After", retryAfterSeconds(wait))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			next(w, r)
+		}
+	}
+}
+
+// jwtSubjectOrClientIP returns the 'sub' claim of the JWT of the request, or the client IP if there is none.
+func jwtSubjectOrClientIP(r *http.Request) string {
+	if claims, ok := JWTClaimsFromContext[map[string]any](r.Context(), nil); ok {
+		if subject, ok := claims["sub"].(string); ok && subject != "" {
+			return subject
+		}
+	}
+
+	return clientIP(r)
+}
@@ -0,0 +1,96 @@
+package supermuxer
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimitByUserMiddleware(t *testing.T) {
+	newHandler := func(keyFunc func(*http.Request) string) http.HandlerFunc {
+		return NewRateLimitByUserMiddleware(UserRateLimitConfig{
+			DefaultRPS: 0.1,
+			TierFunc:   func(r *http.Request) string { return r.Header.Get("X-Tier") },
+			TierLimits: map[string]float64{"pro": 0.5, "internal": math.Inf(1)},
+			Burst:      2,
+			KeyFunc:    keyFunc,
+		})(textHandler("ok"))
+	}
+
+	request := func(handler http.HandlerFunc, user, tier string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+		req.RemoteAddr = user + ":1234"
+		req.Header.Set("X-User", user)
+		req.Header.Set("X-Tier", tier)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+
+		return rec
+	}
+
+	tests := []struct {
+		name           string
+		tier           string
+		requests       int
+		wantRetryAfter string
+	}{
+		{name: "default limit", tier: "", requests: 2, wantRetryAfter: "10"},
+		{name: "unknown tier", tier: "trial", requests: 2, wantRetryAfter: "10"},
+		{name: "tier limit", tier: "pro", requests: 2, wantRetryAfter: "2"},
+		{name: "unlimited tier", tier: "internal", requests: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newHandler(func(r *http.Request) string { return r.Header.Get("X-User") })
+
+			for i := range tt.requests {
+				if rec := request(handler, "192.0.2.1", tt.tier); rec.Code != http.StatusOK {
+					t.Fatalf("request %d status = %d, want %d", i+1, rec.Code, http.StatusOK)
+				}
+			}
+
+			if tt.wantRetryAfter == "" {
+				return
+			}
+
+			rec := request(handler, "192.0.2.1", tt.tier)
+			if rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("status above the limit = %d, want %d", rec.Code, http.StatusTooManyRequests)
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Fatalf("Retry-After = %q, want %q", got, tt.wantRetryAfter)
+			}
+
+			if rec := request(handler, "192.0.2.2", tt.tier); rec.Code != http.StatusOK {
+				t.Fatalf("status of another user = %d, want %d", rec.Code, http.StatusOK)
+			}
+		})
+	}
+
+	t.Run("tier change", func(t *testing.T) {
+		handler := newHandler(func(r *http.Request) string { return r.Header.Get("X-User") })
+
+		for range 2 {
+			request(handler, "192.0.2.1", "")
+		}
+
+		if rec := request(handler, "192.0.2.1", "pro"); rec.Code != http.StatusOK {
+			t.Fatalf("status after the upgrade = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("client IP by default", func(t *testing.T) {
+		handler := newHandler(nil)
+
+		for range 2 {
+			request(handler, "192.0.2.1", "")
+		}
+
+		if rec := request(handler, "192.0.2.1", ""); rec.Code != http.StatusTooManyRequests {
+			t.Fatalf("status above the limit = %d, want %d", rec.Code, http.StatusTooManyRequests)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package supermuxer
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// ValidatorFunc validates a request, e.g. decoding its JSON body and checking the fields.
+type ValidatorFunc func(r *http.Request) error
+
+// NewRequestValidationMiddleware creates a middleware that validates the requests before the next handler.
+// Invalid requests get 400 with the '{"error": "..."}' JSON body. The request body is buffered, so both
+// the validator and the next handler can read it.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewRequestValidationMiddleware(validateUser))
+//	superRouter.Post("/users", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'POST /users'
+//		responding with 400 when validateUser returns an error
+func NewRequestValidationMiddleware(validate ValidatorFunc) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if err := validate(r); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package supermuxer
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestValidationMiddleware(t *testing.T) {
+	validateUser := func(r *http.Request) error {
+		var user struct {
+			Name string `json:"name"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+			return errors.New("invalid JSON")
+		}
+
+		if user.Name == "" {
+			return errors.New("name is required")
+		}
+
+		return nil
+	}
+
+	handler := NewRequestValidationMiddleware(validateUser)(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		want   string
+	}{
+		{name: "valid", body: `{"name":"Ada"}`, status: http.StatusOK, want: `{"name":"Ada"}`},
+		{name: "missing field", body: `{}`, status: http.StatusBadRequest, want: `{"error":"name is required"}` + "\n"},
+		{name: "malformed", body: `{"name":`, status: http.StatusBadRequest, want: `{"error":"invalid JSON"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.status || rec.Body.String() != tt.want {
+				t.Fatalf("response = %d %q, want %d %q", rec.Code, rec.Body.String(), tt.status, tt.want)
+			}
+
+			if tt.status == http.StatusBadRequest && rec.Header().Get("Content-Type") != "application/json" {
+				t.Fatalf("Content-Type = %q, want application/json", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package supermuxer
+
+import (
+	"net/http"
+)
+
+// varyWriter adds the 'Vary' header names right before the headers are written, as they cannot be changed afterwards.
+type varyWriter struct {
+	http.ResponseWriter
+	headers     []string
+	wroteHeader bool
+}
+
+func (w *varyWriter) addHeaders() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	for _, name := range w.headers {
+		if !headerHasToken(header, "Vary", name) {
+			header.Add("Vary", name)
+		}
+	}
+}
+
+func (w *varyWriter) WriteHeader(code int) {
+	w.addHeaders()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *varyWriter) Write(b []byte) (int, error) {
+	w.addHeaders()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *varyWriter) Flush() {
+	w.addHeaders()
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *varyWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewVaryMiddleware creates a middleware that adds the header names to the 'Vary' header of every response, so the
+// caches keep a separate response for each of their values. The names already in the 'Vary' header, set by the
+// handler or the other middlewares, are not added again. As headers cannot change once written, the names are added
+// when the handler writes the headers, or when it returns without writing them.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewVaryMiddleware("Accept", "Accept-Language")).Get("/products", handler)
+//
+//	# Result: supermuxer configuration to handle the request for the endpoint 'GET /products'
+//		with the 'Vary: Accept' and 'Vary: Accept-Language' headers
+func NewVaryMiddleware(headers ...string) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			vw := &varyWriter{ResponseWriter: w, headers: headers}
+			defer vw.addHeaders()
+
+			next(vw, r)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package supermuxer
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestVaryMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    []string
+	}{
+		{
+			name:    "chained middlewares",
+			handler: textHandler("products"),
+			want:    []string{"Accept", "Accept-Encoding", "Accept-Language"},
+		},
+		{
+			name:    "empty response",
+			handler: func(http.ResponseWriter, *http.Request) {},
+			want:    []string{"Accept", "Accept-Encoding", "Accept-Language"},
+		},
+		{
+			name: "names set by the handler",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Vary", "accept-encoding, Cookie")
+				w.WriteHeader(http.StatusOK)
+			},
+			want: []string{"accept-encoding, Cookie", "Accept", "Accept-Language"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			New(mux).AddMiddlewares(
+				NewVaryMiddleware("Accept-Encoding"),
+				NewVaryMiddleware("Accept", "Accept-Language"),
+			).Get("/products", tt.handler)
+
+			got := serve(mux, http.MethodGet, "/products").Result().Header.Values("Vary")
+			slices.Sort(got)
+			slices.Sort(tt.want)
+
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Vary = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
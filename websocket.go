@@ -0,0 +1,77 @@
+package supermuxer
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// WebSocketConn is a WebSocket connection created by a WebSocketUpgrader. Its methods match those of the
+// connections of common WebSocket libraries, such as github.com/gorilla/websocket.
+type WebSocketConn interface {
+	// ReadMessage reads the next message, returning its type and content.
+	ReadMessage() (messageType int, p []byte, err error)
+	// WriteMessage writes a message of the type.
+	WriteMessage(messageType int, data []byte) error
+	// Close closes the connection.
+	Close() error
+}
+
+// WebSocketUpgrader upgrades the HTTP connections to WebSocket connections for NewWebSocketUpgradeMiddleware,
+// commonly by adapting a WebSocket library. When the upgrade fails, it must write the error response itself.
+type WebSocketUpgrader interface {
+	Upgrade(w http.ResponseWriter, r *http.Request, header http.Header) (WebSocketConn, error)
+}
+
+type webSocketConnContextKey struct{}
+
+// NewWebSocketUpgradeMiddleware creates a middleware that upgrades the requests with the 'Upgrade: websocket' header
+// with the upgrader, storing the connection in the request context, readable with WebSocketConnFromContext.
+// The next handler owns the connection and must close it. Other requests go directly to the next handler, and
+// failed upgrades do not reach it.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewWebSocketUpgradeMiddleware(upgrader)).Get("/chat", handler)
+//
+//	# Result: supermuxer configuration to handle the WebSocket connections for the endpoint 'GET /chat'
+func NewWebSocketUpgradeMiddleware(upgrader WebSocketUpgrader) MiddlewareFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if !headerHasToken(r.Header, "Upgrade", "websocket") {
+				next(w, r)
+				return
+			}
+
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+
+			next(w, r.WithContext(context.WithValue(r.Context(), webSocketConnContextKey{}, conn)))
+		}
+	}
+}
+
+// WebSocketConnFromContext returns the connection stored by the middleware created by NewWebSocketUpgradeMiddleware.
+func WebSocketConnFromContext(ctx context.Context) (WebSocketConn, bool) {
+	conn, ok := ctx.Value(webSocketConnContextKey{}).(WebSocketConn)
+	return conn, ok
+}
+
+// headerHasToken reports whether the comma-separated values of the header contain the token, ignoring case.
+func headerHasToken(header http.Header, name string, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
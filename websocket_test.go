@@ -0,0 +1,103 @@
+package supermuxer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockWebSocketConn struct {
+	written []string
+	closed  bool
+}
+
+func (c *mockWebSocketConn) ReadMessage() (int, []byte, error) { return 1, []byte("ping"), nil }
+
+func (c *mockWebSocketConn) WriteMessage(messageType int, data []byte) error {
+	c.written = append(c.written, string(data))
+	return nil
+}
+
+func (c *mockWebSocketConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type mockWebSocketUpgrader struct {
+	conn     *mockWebSocketConn
+	err      error
+	upgrades int
+}
+
+func (u *mockWebSocketUpgrader) Upgrade(w http.ResponseWriter, r *http.Request, header http.Header) (WebSocketConn, error) {
+	u.upgrades++
+	if u.err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return nil, u.err
+	}
+
+	w.WriteHeader(http.StatusSwitchingProtocols)
+	return u.conn, nil
+}
+
+func TestWebSocketUpgradeMiddleware(t *testing.T) {
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		conn, ok := WebSocketConnFromContext(r.Context())
+		if !ok {
+			w.Write([]byte("plain"))
+			return
+		}
+		defer conn.Close()
+
+		_, message, _ := conn.ReadMessage()
+		conn.WriteMessage(1, append([]byte("echo "), message...))
+	}
+
+	newRequest := func(upgrade string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+		if upgrade != "" {
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", upgrade)
+		}
+
+		return req
+	}
+
+	t.Run("upgraded connection in context", func(t *testing.T) {
+		upgrader := &mockWebSocketUpgrader{conn: &mockWebSocketConn{}}
+		rec := httptest.NewRecorder()
+		NewWebSocketUpgradeMiddleware(upgrader)(echo)(rec, newRequest("WebSocket"))
+
+		if upgrader.upgrades != 1 || rec.Code != http.StatusSwitchingProtocols {
+			t.Fatalf("upgrades = %d, status = %d, want 1 and %d", upgrader.upgrades, rec.Code, http.StatusSwitchingProtocols)
+		}
+
+		if len(upgrader.conn.written) != 1 || upgrader.conn.written[0] != "echo ping" || !upgrader.conn.closed {
+			t.Fatalf("connection = %+v, want the echo message and closed", upgrader.conn)
+		}
+	})
+
+	t.Run("plain request", func(t *testing.T) {
+		upgrader := &mockWebSocketUpgrader{conn: &mockWebSocketConn{}}
+		rec := httptest.NewRecorder()
+		NewWebSocketUpgradeMiddleware(upgrader)(echo)(rec, newRequest(""))
+
+		if upgrader.upgrades != 0 || rec.Body.String() != "plain" {
+			t.Fatalf("upgrades = %d, body = %q, want 0 and %q", upgrader.upgrades, rec.Body.String(), "plain")
+		}
+	})
+
+	t.Run("failed upgrade", func(t *testing.T) {
+		called := false
+		upgrader := &mockWebSocketUpgrader{err: errors.New("bad handshake")}
+		rec := httptest.NewRecorder()
+		NewWebSocketUpgradeMiddleware(upgrader)(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})(rec, newRequest("websocket"))
+
+		if called || rec.Code != http.StatusBadRequest {
+			t.Fatalf("handler called = %v, status = %d, want no call and %d", called, rec.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package supermuxer
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// statusWriter records the status code written by the next handlers.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func newStatusWriter(w http.ResponseWriter) *statusWriter {
+	return &statusWriter{ResponseWriter: w}
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Status returns the written status code, which is 200 when the handlers wrote nothing.
+func (w *statusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the original http.ResponseWriter.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// StatusRecorder records the status code written by the next handlers, for the middlewares of other packages,
+// like the contrib modules. It implements http.Flusher and reaches the original http.ResponseWriter with
+// http.ResponseController.
+type StatusRecorder struct {
+	statusWriter
+}
+
+// NewStatusRecorder creates a StatusRecorder that writes the response to w.
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{statusWriter{ResponseWriter: w}}
+}
+
+// bufferWriter buffers the status code and body written by the next handlers, so they can be inspected
+// or changed before being sent with flush. The headers are written directly to the original http.ResponseWriter.
+type bufferWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferWriter(w http.ResponseWriter) *bufferWriter {
+	return &bufferWriter{ResponseWriter: w}
+}
+
+func (w *bufferWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.body.Write(b)
+}
+
+// Status returns the written status code, which is 200 when the handlers wrote nothing.
+func (w *bufferWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// flush sends the buffered status code and body to the original http.ResponseWriter.
+func (w *bufferWriter) flush() {
+	if w.body.Len() > 0 && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", http.DetectContentType(w.body.Bytes()))
+	}
+
+	w.ResponseWriter.WriteHeader(w.Status())
+	w.ResponseWriter.Write(w.body.Bytes())
+}
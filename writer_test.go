@@ -0,0 +1,55 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusRecorder(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		want    int
+	}{
+		{name: "nothing written", handler: func(w http.ResponseWriter, r *http.Request) {}, want: http.StatusOK},
+		{name: "body only", handler: textHandler("ok"), want: http.StatusOK},
+		{name: "status code", handler: func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			w.WriteHeader(http.StatusInternalServerError)
+		}, want: http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			sr := NewStatusRecorder(rec)
+			tt.handler(sr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if sr.Status() != tt.want {
+				t.Fatalf("Status() = %d, want %d", sr.Status(), tt.want)
+			}
+
+			if rec.Code != tt.want {
+				t.Fatalf("written status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	t.Run("flush and unwrap", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		sr := NewStatusRecorder(rec)
+
+		if err := http.NewResponseController(sr).Flush(); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+
+		if !rec.Flushed {
+			t.Fatal("the original writer was not flushed")
+		}
+
+		if sr.Unwrap() != rec {
+			t.Fatal("Unwrap() did not return the original writer")
+		}
+	})
+}
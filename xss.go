@@ -0,0 +1,95 @@
+package supermuxer
+
+import (
+	"html"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+// XSSProtectionConfig configures the middleware created by NewXSSProtectionMiddlewareWithConfig.
+type XSSProtectionConfig struct {
+	// Allowlist lists the query parameters and headers whose values are not escaped.
+	Allowlist []string
+}
+
+// xssExemptHeaders are the headers never escaped by the XSS protection middleware,
+// as their values are read by the server and the handlers rather than echoed.
+var xssExemptHeaders = []string{
+	"Accept",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Authorization",
+	"Content-Length",
+	"Content-Type",
+	"Cookie",
+	"If-Match",
+	"If-None-Match",
+}
+
+// NewXSSProtectionMiddleware creates a middleware that mitigates reflected XSS by HTML-escaping, with
+// html.EscapeString, the query parameter and header values before the next handler, and by setting the
+// 'X-XSS-Protection: 1; mode=block' header. The headers read by the server, such as 'Content-Type', 'Cookie' and
+// 'Authorization', are not escaped. Escaping does not replace encoding the values when rendering them.
+//
+// Returns:
+//   - A middleware to be added with AddMiddlewares.
+//
+// Example:
+//
+//	superRouter := supermuxer.New(serveMux)
+//	superRouter.AddMiddlewares(supermuxer.NewXSSProtectionMiddleware()).Get("/search", handler)
+//
+//	# Result: supermuxer configuration to handle the request 'GET /search?q=<script>' with the 'q' query parameter
+//		equal to '&lt;script&gt;'
+func NewXSSProtectionMiddleware() MiddlewareFunc {
+	return NewXSSProtectionMiddlewareWithConfig(XSSProtectionConfig{})
+}
+
+// NewXSSProtectionMiddlewareWithConfig works the same way as NewXSSProtectionMiddleware, but does not escape
+// the values of the query parameters and headers of the allowlist.
+func NewXSSProtectionMiddlewareWithConfig(cfg XSSProtectionConfig) MiddlewareFunc {
+	exemptHeaders := slices.Clone(xssExemptHeaders)
+	for _, name := range cfg.Allowlist {
+		exemptHeaders = append(exemptHeaders, http.CanonicalHeaderKey(name))
+	}
+
+	escapeValues := func(values url.Values) {
+		for name, list := range values {
+			if slices.Contains(cfg.Allowlist, name) {
+				continue
+			}
+
+			for i, value := range list {
+				list[i] = html.EscapeString(value)
+			}
+		}
+	}
+
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-XSS-Protection", "1; mode=block")
+
+			query := r.URL.Query()
+			escapeValues(query)
+			r.URL.RawQuery = query.Encode()
+
+			// The form is only escaped when it was already parsed, otherwise it is parsed from the escaped query.
+			if r.Form != nil {
+				escapeValues(r.Form)
+			}
+
+			for name, list := range r.Header {
+				if slices.Contains(exemptHeaders, name) {
+					continue
+				}
+
+				for i, value := range list {
+					list[i] = html.EscapeString(value)
+				}
+			}
+
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package supermuxer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestXSSProtectionMiddleware(t *testing.T) {
+	payload := `<script>alert("x")</script>`
+	escaped := `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;`
+
+	type received struct {
+		query, form, referer, contentType, raw string
+	}
+
+	newHandler := func(middleware MiddlewareFunc, got *received) http.HandlerFunc {
+		return middleware(func(w http.ResponseWriter, r *http.Request) {
+			*got = received{
+				query:       r.URL.Query().Get("q"),
+				form:        r.FormValue("q"),
+				referer:     r.Header.Get("Referer"),
+				contentType: r.Header.Get("Content-Type"),
+				raw:         r.URL.Query().Get("raw"),
+			}
+		})
+	}
+
+	newRequest := func() *http.Request {
+		query := "q=" + "%3Cscript%3Ealert(%22x%22)%3C%2Fscript%3E" + "&raw=%3Cb%3E"
+		req := httptest.NewRequest(http.MethodGet, "/search?"+query, nil)
+		req.Header.Set("Referer", payload)
+		req.Header.Set("Content-Type", "text/plain; charset=<utf-8>")
+		return req
+	}
+
+	t.Run("escaped values", func(t *testing.T) {
+		var got received
+		rec := httptest.NewRecorder()
+		newHandler(NewXSSProtectionMiddleware(), &got)(rec, newRequest())
+
+		if got.query != escaped || got.form != escaped {
+			t.Fatalf("query = %q, form = %q, want %q", got.query, got.form, escaped)
+		}
+
+		if got.referer != escaped {
+			t.Fatalf("Referer = %q, want %q", got.referer, escaped)
+		}
+
+		if got.contentType != "text/plain; charset=<utf-8>" {
+			t.Fatalf("Content-Type = %q, want it unchanged", got.contentType)
+		}
+
+		if got.raw != "&lt;b&gt;" {
+			t.Fatalf("raw = %q, want %q", got.raw, "&lt;b&gt;")
+		}
+
+		if rec.Header().Get("X-XSS-Protection") != "1; mode=block" {
+			t.Fatalf("X-XSS-Protection = %q, want %q", rec.Header().Get("X-XSS-Protection"), "1; mode=block")
+		}
+	})
+
+	t.Run("parsed form", func(t *testing.T) {
+		var got received
+		req := newRequest()
+		req.ParseForm()
+		newHandler(NewXSSProtectionMiddleware(), &got)(httptest.NewRecorder(), req)
+
+		if got.form != escaped {
+			t.Fatalf("form = %q, want %q", got.form, escaped)
+		}
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		var got received
+		newHandler(NewXSSProtectionMiddlewareWithConfig(XSSProtectionConfig{Allowlist: []string{"raw", "referer"}}), &got)(httptest.NewRecorder(), newRequest())
+
+		if got.raw != "<b>" || got.referer != payload {
+			t.Fatalf("raw = %q, Referer = %q, want them unchanged", got.raw, got.referer)
+		}
+
+		if got.query != escaped {
+			t.Fatalf("query = %q, want %q", got.query, escaped)
+		}
+	})
+}